@@ -0,0 +1,147 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// AdaptiveShape is a curved primitive whose resolution is chosen each time it is drawn, based on how large
+// it appears on screen. Small, distant shapes are drawn with few points and large, close ones with many,
+// so the same scene renders efficiently as a thumbnail and smoothly at high resolutions.
+//
+// Adaptive shapes are transformed with their own Translate, Rotate and Scale methods, or any Matrix,
+// which are applied to the shape at whatever resolution it is drawn at.
+type AdaptiveShape struct {
+	// SegmentLength is the length, in pixels, that the segments around the shape's curves aim for. Defaults to 8.
+	SegmentLength float64
+	// MinRes and MaxRes limit the number of segments around the shape's largest curve. Default to 8 and 128.
+	MinRes, MaxRes int
+	build          func(res int) *Shape
+	radius         float64
+	matrix         Matrix
+	cache          map[int]*Shape
+}
+
+// NewAdaptiveShape creates an adaptive shape from a function that builds it with the given number of segments
+// around its largest curve. radius is the radius of that curve, before any transforms, used to measure
+// the shape's size on screen.
+func NewAdaptiveShape(radius float64, build func(res int) *Shape) *AdaptiveShape {
+	return &AdaptiveShape{
+		SegmentLength: 8,
+		MinRes:        8,
+		MaxRes:        128,
+		build:         build,
+		radius:        radius,
+		matrix:        IdentityMatrix(),
+		cache:         map[int]*Shape{},
+	}
+}
+
+// AdaptiveSphere creates a sphere of the given radius whose resolution adapts to its size on screen.
+func AdaptiveSphere(radius float64) *AdaptiveShape {
+	return NewAdaptiveShape(radius, func(res int) *Shape {
+		return Sphere(radius, res/2, res, true, true)
+	})
+}
+
+// AdaptiveCircle creates a circle of the given radius whose resolution adapts to its size on screen.
+func AdaptiveCircle(radius float64) *AdaptiveShape {
+	return NewAdaptiveShape(radius, func(res int) *Shape {
+		return Circle(radius, res)
+	})
+}
+
+// AdaptiveTorus creates a torus whose resolution adapts to its size on screen.
+// The tube gets fewer segments than the ring, in proportion to its radius.
+func AdaptiveTorus(r1, r2 float64) *AdaptiveShape {
+	return NewAdaptiveShape(r1+r2, func(res int) *Shape {
+		tubeRes := max(6, int(math.Ceil(float64(res)*r2/(r1+r2))))
+		return Torus(r1, r2, 2*math.Pi, res, tubeRes, true, true)
+	})
+}
+
+// Transform adds a matrix transform to the shape.
+func (a *AdaptiveShape) Transform(m Matrix) {
+	a.matrix = m.Multiply(a.matrix)
+}
+
+// Translate translates the shape on all axes.
+func (a *AdaptiveShape) Translate(tx, ty, tz float64) {
+	a.matrix = a.matrix.Translate(tx, ty, tz)
+}
+
+// RotateX rotates the shape around the x-axis.
+func (a *AdaptiveShape) RotateX(angle float64) {
+	a.matrix = a.matrix.RotateX(angle)
+}
+
+// RotateY rotates the shape around the y-axis.
+func (a *AdaptiveShape) RotateY(angle float64) {
+	a.matrix = a.matrix.RotateY(angle)
+}
+
+// RotateZ rotates the shape around the z-axis.
+func (a *AdaptiveShape) RotateZ(angle float64) {
+	a.matrix = a.matrix.RotateZ(angle)
+}
+
+// Rotate rotates the shape around all axes.
+func (a *AdaptiveShape) Rotate(rx, ry, rz float64) {
+	a.matrix = a.matrix.Rotate(rx, ry, rz)
+}
+
+// Scale scales the shape on all axes.
+func (a *AdaptiveShape) Scale(sx, sy, sz float64) {
+	a.matrix = a.matrix.Scale(sx, sy, sz)
+}
+
+// UniScale scales the shape by the same amount on each axis.
+func (a *AdaptiveShape) UniScale(scale float64) {
+	a.matrix = a.matrix.UniScale(scale)
+}
+
+// ResetTransform removes all transforms from the shape.
+func (a *AdaptiveShape) ResetTransform() {
+	a.matrix = IdentityMatrix()
+}
+
+// Res returns the number of segments around the shape's largest curve that it would be drawn with now,
+// based on its current transform and the world's perspective.
+func (a *AdaptiveShape) Res() int {
+	m := a.matrix
+	center := NewPoint(m[3], m[7], m[11])
+	// the largest scale the transform applies in any direction.
+	scale := 0.0
+	for col := range 3 {
+		scale = math.Max(scale, math.Sqrt(m[col]*m[col]+m[4+col]*m[4+col]+m[8+col]*m[8+col]))
+	}
+	pixels := a.radius * scale * world.FL / math.Max(world.CZ+center.Z, minDepth)
+	res := int(math.Ceil(2 * math.Pi * pixels / a.SegmentLength))
+	res = min(max(res, a.MinRes), a.MaxRes)
+	// round up to a multiple of 4, so small changes in size don't keep building new shapes.
+	return (res + 3) / 4 * 4
+}
+
+// Shape returns the shape at the resolution it would be drawn with now, with its transforms applied.
+// The shape is cached and reused, so it should not be changed.
+func (a *AdaptiveShape) Shape() *Shape {
+	res := a.Res()
+	s, ok := a.cache[res]
+	if !ok {
+		s = a.build(res)
+		s.KeepOriginal()
+		a.cache[res] = s
+	}
+	s.ResetTransform()
+	s.Transform(a.matrix)
+	s.ApplyTransform()
+	return s
+}
+
+// Stroke strokes the shape at the resolution that suits its current size on screen.
+func (a *AdaptiveShape) Stroke(width float64) {
+	a.Shape().Stroke(width)
+}
+
+// RenderPoints draws a filled circle for each point of the shape, at the resolution that suits its current size on screen.
+func (a *AdaptiveShape) RenderPoints(radius float64) {
+	a.Shape().RenderPoints(radius)
+}
@@ -0,0 +1,32 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+// VectorField gives the velocity of the flow at a point and time, such as wind or a swirl.
+type VectorField func(p *Point, t float64) (dx, dy, dz float64)
+
+// Advect moves each point along the flow of the field for a time step of dt, starting at time t.
+// Points are moved with the midpoint method, which follows curved flows much more closely than
+// moving straight along the velocity at the start of the step, so swirls don't spiral outward.
+func (p PointList) Advect(field VectorField, t, dt float64) {
+	for _, point := range p {
+		dx, dy, dz := field(point, t)
+		mid := NewPoint(point.X+dx*dt/2, point.Y+dy*dt/2, point.Z+dz*dt/2)
+		dx, dy, dz = field(mid, t+dt/2)
+		point.Translate(dx*dt, dy*dt, dz*dt)
+	}
+}
+
+// Advect moves each point of the shape along the flow of the field for a time step of dt, in place.
+// Call it once per frame to have the shape melt, dissolve into swirls, or blow away in the wind.
+// The shape keeps count of the time it has been advected for, which is passed to the field,
+// so fields can change over time. See AdvectTime.
+func (s *Shape) Advect(field VectorField, dt float64) {
+	s.ApplyTransform()
+	s.Points.Advect(field, s.advectTime, dt)
+	s.advectTime += dt
+}
+
+// AdvectTime returns the total time the shape has been advected for.
+func (s *Shape) AdvectTime() float64 {
+	return s.advectTime
+}
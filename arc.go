@@ -0,0 +1,94 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math"
+
+	"github.com/bit101/bitlib/blmath"
+)
+
+// ArcSegment is a circular arc that is drawn as a true curve. Rather than being made of a fixed number
+// of straight segments, it is flattened into as many lines as it needs each time it is stroked,
+// based on how large it appears on screen, so it stays smooth however close the camera gets.
+//
+// The arc is defined by its center and two axis points, one radius away from the center in the directions
+// of angle 0 and angle Pi/2. The point at angle t is Center + (PointU - Center) * cos(t) + (PointV - Center) * sin(t).
+// Transforming these three points transforms the arc, so it can be moved, rotated and scaled like any
+// other geometry. Non-uniform scales and shears turn it into an elliptical arc.
+type ArcSegment struct {
+	Center, PointU, PointV *Point
+	Start, End             float64
+}
+
+// arcTolerance is the greatest distance, in pixels, that a flattened arc may stray from the true curve.
+const arcTolerance = 0.25
+
+// NewArcSegment creates a new arc of the given radius around center, from start to end angle, in radians.
+// The arc lies in the plane perpendicular to the given axis. Angle 0 is along the positive y-axis for arcs
+// around the x-axis, along the positive z-axis for arcs around the y-axis, and along the positive x-axis
+// for arcs around the z-axis.
+func NewArcSegment(center *Point, radius, start, end float64, axis Axis) *ArcSegment {
+	u := NewPoint(0, 0, 0)
+	v := NewPoint(0, 0, 0)
+	_, ux, _ := u.axisCoords(axis)
+	_, _, vx := v.axisCoords(axis)
+	*ux, *vx = radius, radius
+	u.Translate(center.X, center.Y, center.Z)
+	v.Translate(center.X, center.Y, center.Z)
+	return &ArcSegment{center, u, v, start, end}
+}
+
+// Points returns the center and two axis points of the arc, which can be transformed to transform the arc.
+func (a *ArcSegment) Points() PointList {
+	return PointList{a.Center, a.PointU, a.PointV}
+}
+
+// PointAt returns a new point on the arc at the given angle.
+func (a *ArcSegment) PointAt(angle float64) *Point {
+	c, s := math.Cos(angle), math.Sin(angle)
+	return NewPoint(
+		a.Center.X+(a.PointU.X-a.Center.X)*c+(a.PointV.X-a.Center.X)*s,
+		a.Center.Y+(a.PointU.Y-a.Center.Y)*c+(a.PointV.Y-a.Center.Y)*s,
+		a.Center.Z+(a.PointU.Z-a.Center.Z)*c+(a.PointV.Z-a.Center.Z)*s,
+	)
+}
+
+// Stroke draws the arc as a smooth curve.
+func (a *ArcSegment) Stroke(width float64) {
+	count := a.flatten()
+	p0 := a.PointAt(a.Start)
+	p0.Project()
+	for i := 1; i <= count; i++ {
+		p1 := a.PointAt(blmath.Lerp(float64(i)/float64(count), a.Start, a.End))
+		p1.Project()
+		NewSegment(p0, p1).Stroke(width)
+		p0 = p1
+	}
+}
+
+// flatten returns the number of straight lines needed to draw the arc within arcTolerance of the true curve,
+// based on its current size on screen.
+func (a *ArcSegment) flatten() int {
+	// the largest on screen radius, measured from the projected center to points around the full circle.
+	radius := 0.0
+	a.Center.Project()
+	for i := range 8 {
+		p := a.PointAt(float64(i) * math.Pi / 4)
+		p.Project()
+		radius = math.Max(radius, math.Hypot(p.Px-a.Center.Px, p.Py-a.Center.Py))
+	}
+	sweep := math.Abs(a.End - a.Start)
+	if radius <= arcTolerance {
+		return 1
+	}
+	// each line of a circle subtending this angle strays at most arcTolerance from the curve.
+	step := 2 * math.Acos(1-arcTolerance/radius)
+	return min(max(1, int(math.Ceil(sweep/step))), 1024)
+}
+
+// AddArc adds an arc to the shape. The arc's center and axis points are added to the shape's points,
+// so the arc follows the shape's transforms. They are rendered along with the other points by RenderPoints.
+func (s *Shape) AddArc(arc *ArcSegment) {
+	s.Points = append(s.Points, arc.Points()...)
+	s.Arcs = append(s.Arcs, arc)
+}
@@ -0,0 +1,66 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math"
+
+	"github.com/bit101/bitlib/blmath"
+)
+
+// Waveform creates a single path through a buffer of audio samples, lying on the xy plane
+// and centered on the origin. The samples are spread evenly across the given width.
+// Samples from -1 to 1 fill a height of a quarter of the width, with positive samples at the top (negative y).
+func Waveform(samples []float64, width float64) *Shape {
+	shape := NewShape()
+	if len(samples) == 0 {
+		return shape
+	}
+	height := width / 4
+	for i, sample := range samples {
+		x := -width / 2
+		if len(samples) > 1 {
+			x = blmath.Map(float64(i), 0, float64(len(samples)-1), -width/2, width/2)
+		}
+		shape.AddXYZ(x, -sample*height/2, 0)
+		if i > 0 {
+			shape.AddSegmentByIndex(i-1, i)
+		}
+	}
+	return shape
+}
+
+// SpectrumBars creates a bar chart of the magnitudes in an fft buffer, lying on the xy plane.
+// Each bar is a rectangle whose length is its magnitude times radius, so magnitudes from 0 to 1 are expected.
+// If radial is true, the bars are spaced evenly around a circle of the given radius, clockwise from the top,
+// and grow outward from it. Otherwise the bars stand in a row on the x-axis, from -radius to radius,
+// and grow upward (towards negative y).
+func SpectrumBars(fft []float64, radius float64, radial bool) *Shape {
+	shape := NewShape()
+	count := float64(len(fft))
+	for i, mag := range fft {
+		// the center of the bar's base, the direction it grows in, and the space given to each bar.
+		var cx, cy, dx, dy, spacing float64
+		if radial {
+			angle := blmath.Tau * float64(i) / count
+			dx, dy = math.Sin(angle), -math.Cos(angle)
+			cx, cy = dx*radius, dy*radius
+			spacing = blmath.Tau * radius / count
+		} else {
+			dx, dy = 0, -1
+			spacing = radius * 2 / count
+			cx = -radius + spacing*(float64(i)+0.5)
+		}
+		// half the bar's width, perpendicular to its direction.
+		px, py := -dy*spacing*0.35, dx*spacing*0.35
+		length := mag * radius
+		start := len(shape.Points)
+		shape.AddXYZ(cx-px, cy-py, 0)
+		shape.AddXYZ(cx+px, cy+py, 0)
+		shape.AddXYZ(cx+px+dx*length, cy+py+dy*length, 0)
+		shape.AddXYZ(cx-px+dx*length, cy-py+dy*length, 0)
+		for j := range 4 {
+			shape.AddSegmentByIndex(start+j, start+(j+1)%4)
+		}
+	}
+	return shape
+}
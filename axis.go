@@ -0,0 +1,54 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// Axis is one of the three axes of 3d space, used by modifiers that work along a chosen axis.
+type Axis int
+
+const (
+	// AxisX is the x-axis.
+	AxisX Axis = iota
+	// AxisY is the y-axis.
+	AxisY
+	// AxisZ is the z-axis.
+	AxisZ
+)
+
+// axisCoords returns pointers to the coordinate of this point along the given axis,
+// followed by the two coordinates across it.
+func (p *Point) axisCoords(axis Axis) (*float64, *float64, *float64) {
+	switch axis {
+	case AxisX:
+		return &p.X, &p.Y, &p.Z
+	case AxisY:
+		return &p.Y, &p.Z, &p.X
+	default:
+		return &p.Z, &p.X, &p.Y
+	}
+}
+
+// axisRange returns the minimum and maximum coordinates of the points along the given axis.
+func (p PointList) axisRange(axis Axis) (float64, float64) {
+	min, max := math.MaxFloat64, -math.MaxFloat64
+	for _, point := range p {
+		along, _, _ := point.axisCoords(axis)
+		min = math.Min(min, *along)
+		max = math.Max(max, *along)
+	}
+	return min, max
+}
+
+// stretchScales returns the scales on each axis that stretch by amount along the given axis,
+// while scaling the other two axes by the inverse square root of amount, so the volume stays the same.
+func stretchScales(axis Axis, amount float64) (float64, float64, float64) {
+	across := 1 / math.Sqrt(amount)
+	switch axis {
+	case AxisX:
+		return amount, across, across
+	case AxisY:
+		return across, amount, across
+	default:
+		return across, across, amount
+	}
+}
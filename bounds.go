@@ -0,0 +1,55 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// Bounds is an axis aligned 3d region.
+type Bounds struct {
+	MinX, MinY, MinZ float64
+	MaxX, MaxY, MaxZ float64
+}
+
+// NewBounds creates a new bounds object from its minimum and maximum corners.
+func NewBounds(minX, minY, minZ, maxX, maxY, maxZ float64) Bounds {
+	return Bounds{minX, minY, minZ, maxX, maxY, maxZ}
+}
+
+// CenteredBounds creates a new bounds object of the given size, centered on the origin.
+func CenteredBounds(w, h, d float64) Bounds {
+	return Bounds{-w / 2, -h / 2, -d / 2, w / 2, h / 2, d / 2}
+}
+
+// Size returns the width, height and depth of the bounds.
+func (b Bounds) Size() (float64, float64, float64) {
+	return b.MaxX - b.MinX, b.MaxY - b.MinY, b.MaxZ - b.MinZ
+}
+
+// Contains returns whether the given point is within the bounds.
+func (b Bounds) Contains(p *Point) bool {
+	return p.X >= b.MinX && p.X <= b.MaxX &&
+		p.Y >= b.MinY && p.Y <= b.MaxY &&
+		p.Z >= b.MinZ && p.Z <= b.MaxZ
+}
+
+// GetBounds returns the bounds of the points in this list.
+func (p PointList) GetBounds() Bounds {
+	b := Bounds{
+		math.MaxFloat64, math.MaxFloat64, math.MaxFloat64,
+		-math.MaxFloat64, -math.MaxFloat64, -math.MaxFloat64,
+	}
+	for _, point := range p {
+		b.MinX = math.Min(b.MinX, point.X)
+		b.MinY = math.Min(b.MinY, point.Y)
+		b.MinZ = math.Min(b.MinZ, point.Z)
+		b.MaxX = math.Max(b.MaxX, point.X)
+		b.MaxY = math.Max(b.MaxY, point.Y)
+		b.MaxZ = math.Max(b.MaxZ, point.Z)
+	}
+	return b
+}
+
+// GetBounds returns the bounds of the shape.
+func (s *Shape) GetBounds() Bounds {
+	s.ApplyTransform()
+	return s.Points.GetBounds()
+}
@@ -0,0 +1,316 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+//////////////////////////////////////////////////////////////
+// Shape caching.
+//
+// Expensive generators can be memoized with Cached, keyed by
+// a string built from their inputs with CacheKey:
+//
+// key := wire.CacheKey("terrain", w, d, res, seed)
+// shape := wire.Cached(key, func() *wire.Shape {
+//     return buildTerrain(w, d, res, seed)
+// })
+//
+// Shapes are kept in memory for the life of the program.
+// If a cache directory is set with SetCacheDir, they are also
+// saved to and loaded from disk, so they persist across runs.
+// Problems with the cache directory never stop a shape being
+// generated. They can be checked with CacheError.
+//////////////////////////////////////////////////////////////
+
+var shapeCache = struct {
+	sync.Mutex
+	shapes map[string]*Shape
+	dir    string
+	err    error
+}{shapes: map[string]*Shape{}}
+
+// Hash returns a deterministic content hash of this shape's points, segments and faces.
+// Two shapes with the same geometry and topology, built in the same order, have the same hash.
+func (s *Shape) Hash() string {
+	s.ApplyTransform()
+	h := sha256.New()
+	buf := make([]byte, 8)
+	writeUint := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf, v)
+		h.Write(buf)
+	}
+	indices := map[*Point]int{}
+	writeUint(uint64(len(s.Points)))
+	for i, p := range s.Points {
+		indices[p] = i
+		writeUint(math.Float64bits(p.X))
+		writeUint(math.Float64bits(p.Y))
+		writeUint(math.Float64bits(p.Z))
+	}
+	writeUint(uint64(len(s.Segments)))
+	for _, seg := range s.Segments {
+		writeUint(uint64(indices[seg.PointA]))
+		writeUint(uint64(indices[seg.PointB]))
+	}
+	writeUint(uint64(len(s.Faces)))
+	for _, face := range s.Faces {
+		writeUint(uint64(len(face.Points)))
+		for _, p := range face.Points {
+			writeUint(uint64(indices[p]))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CacheKey returns a hash of a name and a list of inputs, for use as a key with Cached.
+// Inputs are formatted with fmt, so they should be simple values such as numbers and strings.
+func CacheKey(name string, inputs ...any) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s%#v", name, inputs)))
+	return hex.EncodeToString(sum[:])
+}
+
+// SetCacheDir sets a directory where cached shapes will be saved to and loaded from.
+// Pass an empty string to only cache in memory. The default is an empty string.
+func SetCacheDir(dir string) {
+	shapeCache.Lock()
+	defer shapeCache.Unlock()
+	shapeCache.dir = dir
+}
+
+// ClearCache removes all shapes from the memory cache. Does not affect shapes cached on disk.
+func ClearCache() {
+	shapeCache.Lock()
+	defer shapeCache.Unlock()
+	shapeCache.shapes = map[string]*Shape{}
+}
+
+// CacheError returns the last error from reading or writing the cache directory, or nil if there was none.
+// Shapes that can't be loaded from or saved to disk are still generated and cached in memory.
+func CacheError() error {
+	shapeCache.Lock()
+	defer shapeCache.Unlock()
+	return shapeCache.err
+}
+
+// Cached returns the shape stored in the cache under the given key, calling generate to create
+// and store it if it is not there. A copy of the cached shape is returned each time, so it can be
+// transformed freely without affecting the cache.
+// Shapes are saved to disk with their points, segments, faces, arcs, widths, attributes, colors and recipe intact.
+func Cached(key string, generate func() *Shape) *Shape {
+	shapeCache.Lock()
+	shape, ok := shapeCache.shapes[key]
+	dir := shapeCache.dir
+	shapeCache.Unlock()
+	if ok {
+		return shape.Clone()
+	}
+
+	fileName := ""
+	if dir != "" {
+		fileName = filepath.Join(dir, key+".wirecache")
+		shape, err := readCacheFile(fileName)
+		if err == nil {
+			shapeCache.Lock()
+			shapeCache.shapes[key] = shape
+			shapeCache.Unlock()
+			return shape.Clone()
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			setCacheError(err)
+		}
+	}
+
+	shape = generate()
+	if fileName != "" {
+		if err := writeCacheFile(fileName, shape); err != nil {
+			setCacheError(err)
+		}
+	}
+	shapeCache.Lock()
+	shapeCache.shapes[key] = shape
+	shapeCache.Unlock()
+	return shape.Clone()
+}
+
+// setCacheError records an error from the cache directory, for CacheError.
+func setCacheError(err error) {
+	shapeCache.Lock()
+	defer shapeCache.Unlock()
+	shapeCache.err = err
+}
+
+// cacheFile is everything about a shape that is saved to the cache directory.
+// Points and segments are stored by index, and coordinates at full precision, so shapes load back exactly.
+type cacheFile struct {
+	Points    [][3]float64
+	Attrs     []map[string]float64
+	Segments  [][2]int
+	Widths    []float64
+	Faces     [][]int
+	Arcs      []cacheArc
+	ColorAttr string
+	Palette   Palette
+	Recipe    *Recipe
+}
+
+// cacheArc is an arc, with its points stored by index.
+type cacheArc struct {
+	Center, PointU, PointV int
+	Start, End             float64
+}
+
+// writeCacheFile saves a shape to the cache directory, creating the directory if needed.
+// The file is written under a temporary name and then renamed, so a failed write never leaves a partial file.
+func writeCacheFile(fileName string, s *Shape) error {
+	s.ApplyTransform()
+	indices := make(map[*Point]int, len(s.Points))
+	data := cacheFile{
+		Points:    make([][3]float64, len(s.Points)),
+		Attrs:     make([]map[string]float64, len(s.Points)),
+		ColorAttr: s.ColorAttr,
+		Palette:   s.Palette,
+		Recipe:    s.Recipe,
+	}
+	for i, p := range s.Points {
+		indices[p] = i
+		data.Points[i] = [3]float64{p.X, p.Y, p.Z}
+		data.Attrs[i] = p.Attrs
+	}
+	index := func(p *Point) (int, error) {
+		i, ok := indices[p]
+		if !ok {
+			return 0, errors.New("unable to cache shape: it uses a point that is not in its point list")
+		}
+		return i, nil
+	}
+	for _, seg := range s.Segments {
+		a, err := index(seg.PointA)
+		if err != nil {
+			return err
+		}
+		b, err := index(seg.PointB)
+		if err != nil {
+			return err
+		}
+		data.Segments = append(data.Segments, [2]int{a, b})
+		data.Widths = append(data.Widths, seg.Width)
+	}
+	for _, face := range s.Faces {
+		points := make([]int, len(face.Points))
+		for i, p := range face.Points {
+			j, err := index(p)
+			if err != nil {
+				return err
+			}
+			points[i] = j
+		}
+		data.Faces = append(data.Faces, points)
+	}
+	for _, arc := range s.Arcs {
+		arcPoints := [3]int{}
+		for i, p := range []*Point{arc.Center, arc.PointU, arc.PointV} {
+			j, err := index(p)
+			if err != nil {
+				return err
+			}
+			arcPoints[i] = j
+		}
+		data.Arcs = append(data.Arcs, cacheArc{arcPoints[0], arcPoints[1], arcPoints[2], arc.Start, arc.End})
+	}
+
+	dir := filepath.Dir(fileName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if err := gob.NewEncoder(file).Encode(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), fileName)
+}
+
+// readCacheFile loads a shape saved by writeCacheFile.
+func readCacheFile(fileName string) (*Shape, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data := cacheFile{}
+	if err := gob.NewDecoder(file).Decode(&data); err != nil {
+		return nil, fmt.Errorf("unable to read cached shape %s: %w", fileName, err)
+	}
+
+	shape := NewShape()
+	for i, p := range data.Points {
+		point := NewPoint(p[0], p[1], p[2])
+		if i < len(data.Attrs) {
+			point.Attrs = data.Attrs[i]
+		}
+		shape.AddPoint(point)
+	}
+	get := func(i int) (*Point, error) {
+		if i < 0 || i >= len(shape.Points) {
+			return nil, fmt.Errorf("unable to read cached shape %s: point index %d out of range", fileName, i)
+		}
+		return shape.Points[i], nil
+	}
+	if len(data.Widths) != len(data.Segments) {
+		return nil, fmt.Errorf("unable to read cached shape %s: segment widths don't match segments", fileName)
+	}
+	for i, seg := range data.Segments {
+		a, err := get(seg[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := get(seg[1])
+		if err != nil {
+			return nil, err
+		}
+		shape.AddSegment(&Segment{a, b, data.Widths[i]})
+	}
+	for _, indices := range data.Faces {
+		face := NewFace()
+		for _, i := range indices {
+			p, err := get(i)
+			if err != nil {
+				return nil, err
+			}
+			face.Points.Add(p)
+		}
+		shape.AddFace(face)
+	}
+	for _, arc := range data.Arcs {
+		points := [3]*Point{}
+		for i, j := range []int{arc.Center, arc.PointU, arc.PointV} {
+			p, err := get(j)
+			if err != nil {
+				return nil, err
+			}
+			points[i] = p
+		}
+		shape.Arcs = append(shape.Arcs, &ArcSegment{points[0], points[1], points[2], arc.Start, arc.End})
+	}
+	shape.ColorAttr = data.ColorAttr
+	shape.Palette = data.Palette
+	shape.Recipe = data.Recipe
+	return shape, nil
+}
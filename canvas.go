@@ -0,0 +1,91 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// canvasSetup holds the settings InitWorldForCanvas works from, changed by WorldOptions.
+type canvasSetup struct {
+	fov         float64
+	horizontal  bool
+	distance    float64
+	near, far   float64
+	supersample float64
+}
+
+// WorldOption changes how InitWorldForCanvas sets up the world.
+type WorldOption func(*canvasSetup)
+
+// WithVerticalFOV sets the field of view, in degrees, from the top of the canvas to the bottom.
+// Default is 60.
+func WithVerticalFOV(degrees float64) WorldOption {
+	return func(c *canvasSetup) {
+		c.fov = degrees
+		c.horizontal = false
+	}
+}
+
+// WithHorizontalFOV sets the field of view, in degrees, from the left of the canvas to the right,
+// so the view across stays the same whatever the canvas's aspect ratio.
+func WithHorizontalFOV(degrees float64) WorldOption {
+	return func(c *canvasSetup) {
+		c.fov = degrees
+		c.horizontal = true
+	}
+}
+
+// WithCameraDistance sets how far the camera is from the origin along the z-axis.
+// Default is the focal length, so that the origin lies on the focal plane, where one unit is one pixel.
+func WithCameraDistance(distance float64) WorldOption {
+	return func(c *canvasSetup) {
+		c.distance = distance
+	}
+}
+
+// WithClipping sets the near and far clipping distances, as with SetClipping.
+// Default is a twentieth of the focal length for near, and a hundred times it for far.
+func WithClipping(near, far float64) WorldOption {
+	return func(c *canvasSetup) {
+		c.near, c.far = near, far
+	}
+}
+
+// WithSupersampling sets how many times larger the canvas is than the final output, as with SetSupersampling.
+// Default is 1.
+func WithSupersampling(factor float64) WorldOption {
+	return func(c *canvasSetup) {
+		c.supersample = factor
+	}
+}
+
+// InitWorldForCanvas initializes the world for a canvas of the given width and height in one call,
+// centering it on the canvas, and setting the focal length from a field of view, so the same scene looks
+// the same on canvases of any size. The camera distance, clipping and supersampling are set too.
+// By default, the field of view is 60 degrees vertically, and the origin lies on the focal plane.
+// See the With... options to change these.
+func InitWorldForCanvas(context Context, w, h float64, opts ...WorldOption) {
+	setup := canvasSetup{fov: 60, supersample: 1}
+	for _, opt := range opts {
+		opt(&setup)
+	}
+	size := h
+	if setup.horizontal {
+		size = w
+	}
+	fl := fovToFL(setup.fov, size)
+	if setup.distance == 0 {
+		setup.distance = fl
+	}
+	if setup.near == 0 && setup.far == 0 {
+		setup.near, setup.far = fl/20, fl*100
+	}
+	InitWorld(context, w/2, h/2, setup.distance)
+	world.Width, world.Height = w, h
+	SetPerspective(fl)
+	SetClipping(setup.near, setup.far)
+	SetSupersampling(setup.supersample)
+}
+
+// fovToFL returns the focal length that fits the given field of view, in degrees, across a distance in pixels.
+func fovToFL(degrees, size float64) float64 {
+	return size / 2 / math.Tan(degrees*math.Pi/360)
+}
@@ -0,0 +1,184 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math"
+	"math/rand"
+)
+
+// ChunkedShape is a point cloud split into cubic cells of space, for clouds far too large to draw every point
+// of every frame, such as scans of whole cities. Each frame, only the cells inside the clipping planes,
+// and on screen if the screen size is set, are drawn, and distant cells are drawn with fewer points.
+//
+// Chunked shapes are transformed with their own Translate, Rotate and Scale methods, or any Matrix,
+// which are applied only to the points that are drawn.
+type ChunkedShape struct {
+	// CellSize is the width of each cubic cell.
+	CellSize float64
+	// LODDistance is the depth beyond which cells are drawn with fewer points: every second point at twice
+	// this distance, every third at three times, and so on. 0, the default, always draws every point.
+	LODDistance float64
+	// ScreenWidth and ScreenHeight are the size of the screen. If they are set, cells that are entirely
+	// off screen are not drawn. Default to 0, which only culls cells outside the clipping planes.
+	ScreenWidth, ScreenHeight float64
+	cells                     map[[3]int]*chunkCell
+	order                     []*chunkCell
+	matrix                    Matrix
+}
+
+// chunkCell is one cell of a chunked shape.
+type chunkCell struct {
+	key      [3]int
+	points   PointList
+	shuffled bool
+}
+
+// NewChunkedShape creates a new chunked shape from the given points, split into cells of the given size.
+// The points are not copied.
+func NewChunkedShape(points PointList, cellSize float64) *ChunkedShape {
+	c := &ChunkedShape{
+		CellSize: cellSize,
+		cells:    map[[3]int]*chunkCell{},
+		matrix:   IdentityMatrix(),
+	}
+	for _, p := range points {
+		c.Add(p)
+	}
+	return c
+}
+
+// Add adds a point to the cell it is in.
+func (c *ChunkedShape) Add(p *Point) {
+	key := cellKey(p, c.CellSize)
+	cell, ok := c.cells[key]
+	if !ok {
+		cell = &chunkCell{key: key}
+		c.cells[key] = cell
+		c.order = append(c.order, cell)
+	}
+	cell.points.Add(p)
+	cell.shuffled = false
+}
+
+// Cells returns the number of cells that have points in them.
+func (c *ChunkedShape) Cells() int {
+	return len(c.order)
+}
+
+// Transform adds a matrix transform to the shape.
+func (c *ChunkedShape) Transform(m Matrix) {
+	c.matrix = m.Multiply(c.matrix)
+}
+
+// Translate translates the shape on all axes.
+func (c *ChunkedShape) Translate(tx, ty, tz float64) {
+	c.matrix = c.matrix.Translate(tx, ty, tz)
+}
+
+// RotateX rotates the shape around the x-axis.
+func (c *ChunkedShape) RotateX(angle float64) {
+	c.matrix = c.matrix.RotateX(angle)
+}
+
+// RotateY rotates the shape around the y-axis.
+func (c *ChunkedShape) RotateY(angle float64) {
+	c.matrix = c.matrix.RotateY(angle)
+}
+
+// RotateZ rotates the shape around the z-axis.
+func (c *ChunkedShape) RotateZ(angle float64) {
+	c.matrix = c.matrix.RotateZ(angle)
+}
+
+// Rotate rotates the shape around all axes.
+func (c *ChunkedShape) Rotate(rx, ry, rz float64) {
+	c.matrix = c.matrix.Rotate(rx, ry, rz)
+}
+
+// Scale scales the shape on all axes.
+func (c *ChunkedShape) Scale(sx, sy, sz float64) {
+	c.matrix = c.matrix.Scale(sx, sy, sz)
+}
+
+// UniScale scales the shape by the same amount on each axis.
+func (c *ChunkedShape) UniScale(scale float64) {
+	c.matrix = c.matrix.UniScale(scale)
+}
+
+// ResetTransform removes all transforms from the shape.
+func (c *ChunkedShape) ResetTransform() {
+	c.matrix = IdentityMatrix()
+}
+
+// stride returns how many points of the cell to step over for each one drawn, or 0 if the cell is culled.
+func (c *ChunkedShape) stride(cell *chunkCell) int {
+	minDepth, maxDepth := math.MaxFloat64, -math.MaxFloat64
+	corners := PointList{}
+	for i := range 8 {
+		corner := NewPoint(
+			float64(cell.key[0]+i&1)*c.CellSize,
+			float64(cell.key[1]+i>>1&1)*c.CellSize,
+			float64(cell.key[2]+i>>2&1)*c.CellSize,
+		)
+		corner.Transform(c.matrix)
+		depth := corner.Z + world.CZ
+		minDepth = math.Min(minDepth, depth)
+		maxDepth = math.Max(maxDepth, depth)
+		corners.Add(corner)
+	}
+	if maxDepth < world.NearZ || minDepth > world.FarZ {
+		return 0
+	}
+	// corners behind the camera don't project sensibly, so cells crossing the near plane are always drawn.
+	if c.ScreenWidth > 0 && c.ScreenHeight > 0 && minDepth >= world.NearZ {
+		corners.Project()
+		minX, minY := math.MaxFloat64, math.MaxFloat64
+		maxX, maxY := -math.MaxFloat64, -math.MaxFloat64
+		for _, p := range corners {
+			minX, maxX = math.Min(minX, p.Px), math.Max(maxX, p.Px)
+			minY, maxY = math.Min(minY, p.Py), math.Max(maxY, p.Py)
+		}
+		if maxX < 0 || maxY < 0 || minX > c.ScreenWidth || minY > c.ScreenHeight {
+			return 0
+		}
+	}
+	if c.LODDistance > 0 && minDepth > c.LODDistance {
+		return int(minDepth / c.LODDistance)
+	}
+	return 1
+}
+
+// visiblePoints returns transformed copies of the points that should be drawn now.
+func (c *ChunkedShape) visiblePoints() PointList {
+	points := NewPointList()
+	for _, cell := range c.order {
+		stride := c.stride(cell)
+		if stride == 0 {
+			continue
+		}
+		if stride > 1 && !cell.shuffled {
+			// shuffled once, so the points drawn from a distant cell are spread evenly through it,
+			// not all from one end of a scan line.
+			rng := rand.New(rand.NewSource(int64(len(cell.points))))
+			rng.Shuffle(len(cell.points), func(i, j int) {
+				cell.points[i], cell.points[j] = cell.points[j], cell.points[i]
+			})
+			cell.shuffled = true
+		}
+		for i := 0; i < len(cell.points); i += stride {
+			points.Add(cell.points[i].Transformed(c.matrix))
+		}
+	}
+	return points
+}
+
+// RenderPoints draws a filled circle for each point in the visible cells.
+func (c *ChunkedShape) RenderPoints(radius float64) {
+	c.visiblePoints().RenderPoints(radius)
+}
+
+// RenderSoftPoints draws a soft, radial gradient splat for each point in the visible cells.
+// See PointList.RenderSoftPoints.
+func (c *ChunkedShape) RenderSoftPoints(radius, falloff float64) {
+	c.visiblePoints().RenderSoftPoints(radius, falloff)
+}
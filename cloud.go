@@ -40,17 +40,30 @@ var (
 )
 
 // ShapeFromXYZ creates a new point-only shape from an .xyz formatted point cloud file.
+// Exits with an error if the file can't be read, or if any line can't be parsed.
+// Use ImportXYZ to handle problems instead.
 func ShapeFromXYZ(fileName string) *Shape {
-	pattern, err := regexp.Compile(exp)
+	model, diag, err := ImportXYZ(fileName)
 	if err != nil {
-		fmt.Println(err)
+		log.Fatal("could not open model:", err)
+	}
+	if diag.HasWarnings() {
+		log.Fatalf("couldn't parse %s", diag.Warnings[0])
 	}
+	return model
+}
+
+// ImportXYZ creates a new point-only shape from an .xyz formatted point cloud file, skipping any lines that can't be
+// parsed, and reporting them in the returned diagnostics. An error is only returned if the file can't be read.
+func ImportXYZ(fileName string) (*Shape, *Diagnostics, error) {
+	pattern := regexp.MustCompile(exp)
 
 	// open file
 	model := NewShape()
+	diag := newDiagnostics(fileName)
 	file, err := os.Open(fileName)
 	if err != nil {
-		log.Fatal("could not open model:", err)
+		return nil, nil, err
 	}
 	defer file.Close()
 
@@ -61,34 +74,37 @@ func ShapeFromXYZ(fileName string) *Shape {
 		line := scanner.Text()
 
 		// do regex magic
-		matches := pattern.MatchString(line)
-		if matches {
-			match := pattern.FindStringSubmatch(line)
-			// match[0] is entire match. ignore.
-			x := getFloat(match[1], lineNum)
-			y := getFloat(match[2], lineNum)
-			z := getFloat(match[3], lineNum)
-			model.AddXYZ(x, y, z)
+		match := pattern.FindStringSubmatchIndex(line)
+		if match != nil {
+			// match[0:2] is entire match. ignore.
+			coords := [3]float64{}
+			ok := true
+			for i := range coords {
+				start, end := match[2+i*2], match[3+i*2]
+				coords[i], err = strconv.ParseFloat(line[start:end], 64)
+				if err != nil {
+					diag.warn(lineNum, start+1, "bad number %q", line[start:end])
+					ok = false
+					break
+				}
+			}
+			if ok {
+				model.AddXYZ(coords[0], coords[1], coords[2])
+			}
 		} else if lineNum > 2 {
 			// per xyz spec fisrt two lines are optionally:
 			// 1. number of vertices
 			// 2. comment/space
-			log.Fatalf("couldn't parse line %d: %q", lineNum, line)
+			diag.warn(lineNum, 0, "expected x y z, found %q", line)
 		}
 		lineNum++
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
 
 	// adjust to wire's coord system
 	model.Center()
 	model.Rotate(-math.Pi/2, math.Pi, 0)
-	return model
-}
-
-func getFloat(s string, lineNum int) float64 {
-	val, err := strconv.ParseFloat(s, 64)
-	if err != nil {
-		log.Fatalf("couldn't parse float on line %d: %q", lineNum, s)
-	}
-	return val
-
+	return model, diag, nil
 }
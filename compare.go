@@ -0,0 +1,53 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "github.com/bit101/bitlib/blcolor"
+
+// CompareRender strokes two shapes superimposed, a in colorA and b in colorB, to check how closely they match,
+// such as an imported model against its source, or a decimated shape against the original.
+// If tolerance is greater than zero, any point of either shape with no point of the other shape within tolerance
+// of it is highlighted with a dot in its shape's color, so differences stand out.
+// A tolerance of zero draws the shapes only. Any colorization set on the shapes is ignored.
+func CompareRender(a, b *Shape, colorA, colorB blcolor.Color, width, tolerance float64) {
+	a.strokeColor(width, colorA)
+	b.strokeColor(width, colorB)
+	if tolerance <= 0 {
+		return
+	}
+	highlight(a.Points.unmatched(b.Points, tolerance), width*2, colorA)
+	highlight(b.Points.unmatched(a.Points, tolerance), width*2, colorB)
+}
+
+// highlight draws a dot of the given radius and color at each point. The points must already be projected.
+func highlight(points PointList, radius float64, color blcolor.Color) {
+	colors := make([]blcolor.Color, len(points))
+	for i := range colors {
+		colors[i] = color
+	}
+	points.renderPoints(radius, colors, nil)
+}
+
+// strokeColor strokes each segment of the shape in the given color.
+func (s *Shape) strokeColor(width float64, color blcolor.Color) {
+	s.project()
+	for _, segment := range s.Segments {
+		segment.stroke(width, &color)
+	}
+}
+
+// unmatched returns the points in this list that have no point in other within tolerance of them.
+func (p PointList) unmatched(other PointList, tolerance float64) PointList {
+	grid := NewSpatialGrid(other, tolerance)
+	// the grid never finds a point itself, so points in both lists are matched separately.
+	shared := map[*Point]bool{}
+	for _, point := range other {
+		shared[point] = true
+	}
+	result := NewPointList()
+	for _, point := range p {
+		if !shared[point] && grid.Nearest(point, tolerance) == nil {
+			result.Add(point)
+		}
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math"
+	"slices"
+)
+
+// ConnectNearest creates a shape from the given points, with each point connected to up to k of its
+// nearest neighbors that are within maxDist of it. If k is zero or less, each point is connected to
+// all of its neighbors within maxDist. This gives the classic "plexus" network effect.
+// Does not clone the points, so transforms to the new shape will affect the original points as well.
+func ConnectNearest(points PointList, k int, maxDist float64) *Shape {
+	shape := NewShape()
+	shape.Points = append(shape.Points, points...)
+	grid := NewSpatialGrid(points, maxDist)
+	indices := map[*Point]int{}
+	for i, p := range points {
+		indices[p] = i
+	}
+	edges := map[[2]int]bool{}
+	for i, p := range points {
+		neighbors := grid.Neighbors(p, maxDist)
+		slices.SortFunc(neighbors, func(a, b *Point) int {
+			return cmpFloat(p.Distance(a), p.Distance(b))
+		})
+		if k > 0 && len(neighbors) > k {
+			neighbors = neighbors[:k]
+		}
+		for _, n := range neighbors {
+			j := indices[n]
+			key := [2]int{min(i, j), max(i, j)}
+			if !edges[key] {
+				edges[key] = true
+				shape.AddSegmentByIndex(i, j)
+			}
+		}
+	}
+	return shape
+}
+
+// ConnectDelaunay creates a shape from the given points, connected by the edges of their 3d Delaunay
+// tetrahedralization. Every point is connected to its natural neighbors, with no long edges crossing
+// through the cloud.
+// Does not clone the points, so transforms to the new shape will affect the original points as well.
+func ConnectDelaunay(points PointList) *Shape {
+	shape := NewShape()
+	shape.Points = append(shape.Points, points...)
+	if len(points) < 4 {
+		for i := 0; i < len(points)-1; i++ {
+			for j := i + 1; j < len(points); j++ {
+				shape.AddSegmentByIndex(i, j)
+			}
+		}
+		return shape
+	}
+
+	// Bowyer-Watson, starting with a tetrahedron big enough to contain all the points.
+	// The super tetrahedron's points are added to the end of a working list, and removed at the end.
+	verts := append(PointList{}, points...)
+	w, h, d := points.GetSize()
+	size := math.Max(w, math.Max(h, d)) * 100
+	cx, cy, cz := points.centroid()
+	n := len(points)
+	verts.AddXYZ(cx-size, cy-size, cz-size)
+	verts.AddXYZ(cx+size*3, cy-size, cz-size)
+	verts.AddXYZ(cx-size, cy+size*3, cz-size)
+	verts.AddXYZ(cx-size, cy-size, cz+size*3)
+	tets := []*tetra{newTetra(verts, n, n+1, n+2, n+3)}
+
+	for i := range n {
+		p := verts[i]
+		faces := map[[3]int]int{}
+		kept := []*tetra{}
+		for _, t := range tets {
+			if t.contains(p) {
+				for _, f := range t.faces() {
+					faces[f]++
+				}
+			} else {
+				kept = append(kept, t)
+			}
+		}
+		for f, count := range faces {
+			// faces shared by two removed tetrahedra are interior to the cavity.
+			if count == 1 {
+				kept = append(kept, newTetra(verts, f[0], f[1], f[2], i))
+			}
+		}
+		tets = kept
+	}
+
+	edges := map[[2]int]bool{}
+	for _, t := range tets {
+		for a := 0; a < 3; a++ {
+			for b := a + 1; b < 4; b++ {
+				i, j := t.v[a], t.v[b]
+				if i >= n || j >= n {
+					continue
+				}
+				key := [2]int{min(i, j), max(i, j)}
+				if !edges[key] {
+					edges[key] = true
+					shape.AddSegmentByIndex(i, j)
+				}
+			}
+		}
+	}
+	return shape
+}
+
+// tetra is a tetrahedron referencing points by index, along with its circumsphere.
+type tetra struct {
+	v          [4]int
+	cx, cy, cz float64
+	r2         float64
+}
+
+func newTetra(points PointList, a, b, c, d int) *tetra {
+	t := &tetra{v: [4]int{a, b, c, d}}
+	pa, pb, pc, pd := points[a], points[b], points[c], points[d]
+	ux, uy, uz := pb.X-pa.X, pb.Y-pa.Y, pb.Z-pa.Z
+	vx, vy, vz := pc.X-pa.X, pc.Y-pa.Y, pc.Z-pa.Z
+	wx, wy, wz := pd.X-pa.X, pd.Y-pa.Y, pd.Z-pa.Z
+
+	// cross products
+	vwx, vwy, vwz := vy*wz-vz*wy, vz*wx-vx*wz, vx*wy-vy*wx
+	wux, wuy, wuz := wy*uz-wz*uy, wz*ux-wx*uz, wx*uy-wy*ux
+	uvx, uvy, uvz := uy*vz-uz*vy, uz*vx-ux*vz, ux*vy-uy*vx
+
+	denom := 2 * (ux*vwx + uy*vwy + uz*vwz)
+	if denom == 0 {
+		// flat tetrahedron. make it contain everything so it gets replaced.
+		t.r2 = math.Inf(1)
+		return t
+	}
+	u2 := ux*ux + uy*uy + uz*uz
+	v2 := vx*vx + vy*vy + vz*vz
+	w2 := wx*wx + wy*wy + wz*wz
+	ox := (u2*vwx + v2*wux + w2*uvx) / denom
+	oy := (u2*vwy + v2*wuy + w2*uvy) / denom
+	oz := (u2*vwz + v2*wuz + w2*uvz) / denom
+	t.cx, t.cy, t.cz = pa.X+ox, pa.Y+oy, pa.Z+oz
+	t.r2 = ox*ox + oy*oy + oz*oz
+	return t
+}
+
+// contains returns whether the point is inside this tetrahedron's circumsphere.
+func (t *tetra) contains(p *Point) bool {
+	dx, dy, dz := p.X-t.cx, p.Y-t.cy, p.Z-t.cz
+	return dx*dx+dy*dy+dz*dz < t.r2
+}
+
+// faces returns the four triangular faces of this tetrahedron, each with sorted indices
+// so that shared faces can be matched.
+func (t *tetra) faces() [][3]int {
+	faces := [][3]int{}
+	for skip := range 4 {
+		f := []int{}
+		for i, v := range t.v {
+			if i != skip {
+				f = append(f, v)
+			}
+		}
+		slices.Sort(f)
+		faces = append(faces, [3]int{f[0], f[1], f[2]})
+	}
+	return faces
+}
+
+// cmpFloat compares two floats for sorting.
+func cmpFloat(a, b float64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
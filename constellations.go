@@ -0,0 +1,103 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+//////////////////////////////////////////////////////////////
+// Star catalogs list stars and the lines between them that
+// make up constellation figures, one per line.
+//
+// star id ra dec magnitude
+// line id1 id2 id3 ...
+//
+// ids are any text without spaces, such as a name or catalog number.
+// ra is right ascension in hours, from 0 to 24, and dec is declination
+// in degrees, from -90 to 90. Lines join each star to the next, and must
+// come after the stars they use. Blank lines and lines starting with #
+// are ignored.
+//
+// star betelgeuse 5.919 7.407 0.42
+// star bellatrix 5.419 6.350 1.64
+// line betelgeuse bellatrix
+//////////////////////////////////////////////////////////////
+
+// MagnitudeAttr is the name of the point attribute Constellations sets to each star's magnitude.
+const MagnitudeAttr = "magnitude"
+
+// SizeAttr is the name of the point attribute Constellations sets to each star's size, for RenderPointsByAttr.
+const SizeAttr = "size"
+
+// Constellations creates a celestial sphere of radius 1, centered on the origin, from a star catalog.
+// Each star is a point and each constellation line a segment. The sphere is seen from the inside, as the sky is:
+// from the center, right ascension 0 is straight ahead, along the positive z-axis, north is up and
+// right ascension increases to the left. UniScale it to the size needed.
+//
+// Each star has a "magnitude" attribute, and a "size" attribute for drawing with RenderPointsByAttr,
+// which runs from 1 at magnitude 0 down toward 0 at magnitude 6.5, the faintest stars visible to the naked eye.
+// Brighter stars are larger than 1, and the faintest are never smaller than 0.1.
+func Constellations(catalog io.Reader) (*Shape, error) {
+	shape := NewShape()
+	stars := map[string]*Point{}
+	lineNum := 0
+	scanner := bufio.NewScanner(catalog)
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "star":
+			if len(fields) != 5 {
+				return nil, fmt.Errorf("unable to parse catalog line %d: star needs an id, ra, dec and magnitude", lineNum)
+			}
+			values := [3]float64{}
+			for i, field := range fields[2:] {
+				v, err := strconv.ParseFloat(field, 64)
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse catalog line %d: %s", lineNum, err)
+				}
+				values[i] = v
+			}
+			ra := values[0] / 24 * 2 * math.Pi
+			dec := values[1] * math.Pi / 180
+			star := NewPoint(
+				-math.Cos(dec)*math.Sin(ra),
+				-math.Sin(dec),
+				math.Cos(dec)*math.Cos(ra),
+			)
+			star.SetAttr(MagnitudeAttr, values[2])
+			star.SetAttr(SizeAttr, math.Max(0.1, 1-values[2]/6.5))
+			stars[fields[1]] = star
+			shape.AddPoint(star)
+
+		case "line":
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("unable to parse catalog line %d: line needs at least two stars", lineNum)
+			}
+			for i := 2; i < len(fields); i++ {
+				a, okA := stars[fields[i-1]]
+				b, okB := stars[fields[i]]
+				if !okA || !okB {
+					return nil, fmt.Errorf("unknown star on catalog line %d", lineNum)
+				}
+				shape.AddSegmentByPoints(a, b)
+			}
+
+		default:
+			return nil, fmt.Errorf("unable to parse catalog line %d: unknown entry %q", lineNum, fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New("unable to load catalog: " + err.Error())
+	}
+	return shape, nil
+}
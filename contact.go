@@ -0,0 +1,43 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// SceneFunc draws a scene of the given size at time t, for ContactSheet.
+// The world is already centered on the scene's tile, so it should draw without calling InitWorld or SetCenter.
+type SceneFunc func(width, height, t float64)
+
+// clipper is implemented by contexts that can clip drawing to a rectangle, such as a cairo context.
+type clipper interface {
+	Rectangle(x, y, w, h float64)
+	Clip()
+}
+
+// ContactSheet draws several scenes side by side in a grid of equal tiles filling a w by h image,
+// with the given number of columns, each at time t. It's useful for comparing variations of a scene,
+// such as the same generative system with different parameters.
+// Each scene is drawn with the world centered on its tile, keeping the current z center, and any changes
+// a scene makes to the world, such as its color or fog, are undone before the next one.
+// If the context supports clipping, each scene is clipped to its tile.
+func ContactSheet(scenes []SceneFunc, cols int, w, h float64, t float64) {
+	if len(scenes) == 0 {
+		return
+	}
+	cols = max(1, min(cols, len(scenes)))
+	rows := int(math.Ceil(float64(len(scenes)) / float64(cols)))
+	tw, th := w/float64(cols), h/float64(rows)
+	saved := world
+	for i, scene := range scenes {
+		x := float64(i%cols) * tw
+		y := float64(i/cols) * th
+		world.Context.Save()
+		if c, ok := world.Context.(clipper); ok {
+			c.Rectangle(x, y, tw, th)
+			c.Clip()
+		}
+		SetCenter(x+tw/2, y+th/2, saved.CZ)
+		scene(tw, th, t)
+		world = saved
+		world.Context.Restore()
+	}
+}
@@ -0,0 +1,97 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/bit101/bitlib/blcolor"
+)
+
+// DensityAttr is the name of the point attribute DensityGrid sets to the number of points in the cells
+// around each corner.
+const DensityAttr = "density"
+
+// PaletteDensity is a palette for DensityGrid, running from transparent to white,
+// so empty-looking cells fade out and dense ones stand out.
+var PaletteDensity = NewPalette(
+	blcolor.RGBA(1, 1, 1, 0),
+	blcolor.RGB(1, 1, 1),
+)
+
+// DensityGrid creates a lattice of cubic cells of the given size around a point cloud, with a cell wherever
+// there are points, showing how many points fall inside each one rather than drawing the points themselves.
+// This gives a view of clouds far too large to draw point by point.
+//
+// Each edge has a width, as a multiplier on the stroke width, from the number of points in the densest cell
+// it borders, relative to the densest cell of all. Each corner has a "density" attribute of the number of points
+// in the densest cell around it, and the shape is colored by it through PaletteDensity,
+// so sparse cells are faint and dense ones bright. Call ColorizeByAttr to use another palette.
+func DensityGrid(points PointList, cellSize float64) *Shape {
+	counts := map[[3]int]int{}
+	for _, p := range points {
+		counts[cellKey(p, cellSize)]++
+	}
+	cells := make([][3]int, 0, len(counts))
+	maxCount := 0
+	for key, count := range counts {
+		cells = append(cells, key)
+		maxCount = max(maxCount, count)
+	}
+	slices.SortFunc(cells, func(a, b [3]int) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]), cmp.Compare(a[2], b[2]))
+	})
+
+	shape := NewShape()
+	corners := map[[3]int]*Point{}
+	corner := func(key [3]int, count int) *Point {
+		p, ok := corners[key]
+		if !ok {
+			p = NewPoint(float64(key[0])*cellSize, float64(key[1])*cellSize, float64(key[2])*cellSize)
+			corners[key] = p
+			shape.AddPoint(p)
+		}
+		if value, _ := p.GetAttr(DensityAttr); float64(count) > value {
+			p.SetAttr(DensityAttr, float64(count))
+		}
+		return p
+	}
+	// edges are keyed by their first corner and the axis they run along, so edges shared by neighboring cells
+	// are only added once, taking the width of the densest of them.
+	edges := map[[4]int]*Segment{}
+	for _, cell := range cells {
+		count := counts[cell]
+		width := float64(count) / float64(maxCount)
+		for axis := range 3 {
+			for i := range 4 {
+				a := cell
+				a[(axis+1)%3] += i & 1
+				a[(axis+2)%3] += i >> 1
+				b := a
+				b[axis]++
+				key := [4]int{a[0], a[1], a[2], axis}
+				pa, pb := corner(a, count), corner(b, count)
+				if seg, ok := edges[key]; ok {
+					seg.Width = math.Max(seg.Width, width)
+					continue
+				}
+				seg := NewSegment(pa, pb)
+				seg.Width = width
+				edges[key] = seg
+				shape.AddSegment(seg)
+			}
+		}
+	}
+	shape.ColorizeByAttr(DensityAttr, PaletteDensity)
+	return shape
+}
+
+// cellKey returns the index of the cubic cell of the given size that a point is in.
+func cellKey(p *Point, cellSize float64) [3]int {
+	return [3]int{
+		int(math.Floor(p.X / cellSize)),
+		int(math.Floor(p.Y / cellSize)),
+		int(math.Floor(p.Z / cellSize)),
+	}
+}
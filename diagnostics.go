@@ -0,0 +1,115 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Diagnostic describes a problem with one line of an imported file. The line was skipped, or partly skipped,
+// and the import carried on.
+type Diagnostic struct {
+	// Line is the line number of the problem, starting at 1.
+	Line int
+	// Column is the column of the problem within the line, starting at 1, or 0 if it is the whole line.
+	Column  int
+	Message string
+}
+
+// String returns the diagnostic as a message with its line and column.
+func (d Diagnostic) String() string {
+	if d.Column == 0 {
+		return fmt.Sprintf("line %d: %s", d.Line, d.Message)
+	}
+	return fmt.Sprintf("line %d, column %d: %s", d.Line, d.Column, d.Message)
+}
+
+// Diagnostics collects the problems found while importing a file, so that many files can be imported in a batch
+// and any problems reported afterward, rather than stopping at the first one.
+type Diagnostics struct {
+	File     string
+	Warnings []Diagnostic
+}
+
+// newDiagnostics creates an empty set of diagnostics for the given file.
+func newDiagnostics(fileName string) *Diagnostics {
+	return &Diagnostics{fileName, []Diagnostic{}}
+}
+
+// warn adds a warning at the given line and column.
+func (d *Diagnostics) warn(line, column int, format string, args ...any) {
+	d.Warnings = append(d.Warnings, Diagnostic{line, column, fmt.Sprintf(format, args...)})
+}
+
+// HasWarnings returns whether any problems were found.
+func (d *Diagnostics) HasWarnings() bool {
+	return len(d.Warnings) > 0
+}
+
+// String returns every warning on its own line, each starting with the file name.
+func (d *Diagnostics) String() string {
+	lines := []string{}
+	for _, w := range d.Warnings {
+		lines = append(lines, d.File+": "+w.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fieldsWithColumns splits a line into fields separated by white space, like strings.Fields,
+// also returning the column each field starts at, starting at 1.
+func fieldsWithColumns(line string) ([]string, []int) {
+	fields := []string{}
+	columns := []int{}
+	start := -1
+	for i, r := range line + " " {
+		space := r == ' ' || r == '\t' || r == '\r'
+		if !space && start < 0 {
+			start = i
+		}
+		if space && start >= 0 {
+			fields = append(fields, line[start:i])
+			columns = append(columns, start+1)
+			start = -1
+		}
+	}
+	return fields, columns
+}
+
+// parseFloats parses the first count fields of a line as numbers, warning about any that can't be parsed,
+// or if there are too few. Returns false if the numbers couldn't all be parsed.
+func (d *Diagnostics) parseFloats(line int, fields []string, columns []int, count int) ([]float64, bool) {
+	if len(fields) < count {
+		d.warn(line, 0, "expected %d numbers, found %d", count, len(fields))
+		return nil, false
+	}
+	values := make([]float64, count)
+	for i := range count {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			d.warn(line, columns[i], "bad number %q", fields[i])
+			return nil, false
+		}
+		values[i] = v
+	}
+	return values, true
+}
+
+// parseInts parses the first count fields of a line as integers, warning about any that can't be parsed,
+// or if there are too few. Returns false if the integers couldn't all be parsed.
+func (d *Diagnostics) parseInts(line int, fields []string, columns []int, count int) ([]int, bool) {
+	if len(fields) < count {
+		d.warn(line, 0, "expected %d integers, found %d", count, len(fields))
+		return nil, false
+	}
+	values := make([]int, count)
+	for i := range count {
+		v, err := strconv.Atoi(fields[i])
+		if err != nil {
+			d.warn(line, columns[i], "bad integer %q", fields[i])
+			return nil, false
+		}
+		values[i] = v
+	}
+	return values, true
+}
@@ -0,0 +1,179 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math/rand"
+
+	"github.com/bit101/bitlib/noise"
+)
+
+// DisplaceMode is the direction in which NoiseDisplace moves points.
+type DisplaceMode int
+
+const (
+	// DisplaceRadial moves points toward or away from the origin.
+	DisplaceRadial DisplaceMode = iota
+	// DisplaceX moves points along the x-axis.
+	DisplaceX
+	// DisplaceY moves points along the y-axis.
+	DisplaceY
+	// DisplaceZ moves points along the z-axis.
+	DisplaceZ
+	// DisplaceAxes moves points along all three axes, by a separate noise value for each.
+	DisplaceAxes
+	// DisplaceNormal moves points along the shape's estimated surface normal.
+	DisplaceNormal
+)
+
+// fbm returns fractal Brownian motion noise at the given position: octaves of simplex noise,
+// each at twice the frequency and half the amplitude of the last. The result is roughly -1 to 1.
+func fbm(x, y, z float64, octaves int) float64 {
+	n, amp, freq, total := 0.0, 1.0, 1.0, 0.0
+	for range max(1, octaves) {
+		n += noise.Simplex3(x*freq, y*freq, z*freq) * amp
+		total += amp
+		amp /= 2
+		freq *= 2
+	}
+	return n / total
+}
+
+// NoiseDisplace moves each point of the shape by fractal noise, sampled at the point's position.
+// scale is the frequency of the noise, so smaller values give broader lumps, and amplitude is the farthest
+// a point can move, in either direction. Each octave adds detail at twice the frequency and half the strength
+// of the last. The same seed always gives the same displacement. mode sets the direction points move in.
+//
+// For DisplaceNormal, the normal of points on faces is the average of the faces' normals. Points with segments
+// but no faces move away from the average of the points they connect to, which is outward on convex wireframes.
+// Other points move radially.
+func (s *Shape) NoiseDisplace(scale, amplitude float64, octaves int, seed int64, mode DisplaceMode) {
+	s.ApplyTransform()
+	rng := rand.New(rand.NewSource(seed))
+	// separate offsets for each axis, so DisplaceAxes doesn't move every point along a diagonal.
+	offsets := [3][3]float64{}
+	for i := range offsets {
+		for j := range offsets[i] {
+			offsets[i][j] = rng.Float64() * 1000
+		}
+	}
+	sample := func(p *Point, axis int) float64 {
+		o := offsets[axis]
+		return fbm(o[0]+p.X*scale, o[1]+p.Y*scale, o[2]+p.Z*scale, octaves) * amplitude
+	}
+
+	var normals map[*Point]*Point
+	if mode == DisplaceNormal {
+		normals = s.estimatedNormals()
+	}
+
+	// all the noise is sampled before any point moves, so each point's displacement depends only on its own position.
+	moves := make([]*Point, len(s.Points))
+	for i, p := range s.Points {
+		n := sample(p, 0)
+		switch mode {
+		case DisplaceX:
+			moves[i] = NewPoint(n, 0, 0)
+		case DisplaceY:
+			moves[i] = NewPoint(0, n, 0)
+		case DisplaceZ:
+			moves[i] = NewPoint(0, 0, n)
+		case DisplaceAxes:
+			moves[i] = NewPoint(n, sample(p, 1), sample(p, 2))
+		default:
+			dir, ok := normals[p]
+			if !ok {
+				if p.Magnitude() == 0 {
+					moves[i] = NewPoint(0, 0, 0)
+					continue
+				}
+				dir = p.Normalized()
+			}
+			moves[i] = NewPoint(dir.X*n, dir.Y*n, dir.Z*n)
+		}
+	}
+	for i, p := range s.Points {
+		p.Translate(moves[i].X, moves[i].Y, moves[i].Z)
+	}
+}
+
+// NoiseJitter moves each point of the shape by up to amount on each axis, by simplex noise sampled at its position.
+// Unlike Randomize, nearby points move together, giving a coherent, hand drawn wobble rather than static.
+// scale is the frequency of the noise, so smaller values make larger areas move together.
+// The same seed always gives the same jitter. This is NoiseDisplace with a single octave and DisplaceAxes.
+func (s *Shape) NoiseJitter(scale, amount float64, seed int64) {
+	s.NoiseDisplace(scale, amount, 1, seed, DisplaceAxes)
+}
+
+// estimatedNormals returns an estimated unit normal for each point of the shape that is on a face or segment.
+// Face points use the vertex normals of the faces. Other points with segments use the direction
+// from the average of their connected points to the point itself, if they are not at that average.
+func (s *Shape) estimatedNormals() map[*Point]*Point {
+	normals := s.vertexNormals()
+	sums := map[*Point]*Point{}
+	counts := map[*Point]float64{}
+	add := func(p, neighbor *Point) {
+		if _, ok := normals[p]; ok {
+			return
+		}
+		sum, ok := sums[p]
+		if !ok {
+			sum = NewPoint(0, 0, 0)
+			sums[p] = sum
+		}
+		sum.Translate(neighbor.X, neighbor.Y, neighbor.Z)
+		counts[p]++
+	}
+	for _, seg := range s.Segments {
+		add(seg.PointA, seg.PointB)
+		add(seg.PointB, seg.PointA)
+	}
+	for p, sum := range sums {
+		c := counts[p]
+		n := NewPoint(p.X-sum.X/c, p.Y-sum.Y/c, p.Z-sum.Z/c)
+		if n.Magnitude() == 0 {
+			continue
+		}
+		n.Normalize()
+		normals[p] = n
+	}
+	return normals
+}
+
+// CurlDisplace moves each point of the shape along a curl noise field, sampled at the point's position.
+// Curl noise is divergence free, so it never bunches points together or spreads them apart,
+// but swirls them around each other, like smoke. scale is the frequency of the field, so smaller values
+// give broader swirls, and amount is roughly the farthest a point can move. The same seed always gives the same field.
+func (s *Shape) CurlDisplace(scale, amount float64, seed int64) {
+	s.ApplyTransform()
+	rng := rand.New(rand.NewSource(seed))
+	// the field is the curl of a vector potential made of three unrelated noise fields.
+	offsets := [3][3]float64{}
+	for i := range offsets {
+		for j := range offsets[i] {
+			offsets[i][j] = rng.Float64() * 1000
+		}
+	}
+	potential := func(i int, x, y, z float64) float64 {
+		o := offsets[i]
+		return noise.Simplex3(o[0]+x, o[1]+y, o[2]+z)
+	}
+	// partial derivatives are found by central differences, in noise space.
+	// the curl of simplex noise rarely exceeds 10 in length, so dividing by 10 makes amount the farthest move.
+	const h = 1e-4
+	amount /= 10
+	moves := make([]*Point, len(s.Points))
+	for i, p := range s.Points {
+		x, y, z := p.X*scale, p.Y*scale, p.Z*scale
+		d := func(field int, dx, dy, dz float64) float64 {
+			return (potential(field, x+dx, y+dy, z+dz) - potential(field, x-dx, y-dy, z-dz)) / (2 * h)
+		}
+		moves[i] = NewPoint(
+			(d(2, 0, h, 0)-d(1, 0, 0, h))*amount,
+			(d(0, 0, 0, h)-d(2, h, 0, 0))*amount,
+			(d(1, h, 0, 0)-d(0, 0, h, 0))*amount,
+		)
+	}
+	for i, p := range s.Points {
+		p.Translate(moves[i].X, moves[i].Y, moves[i].Z)
+	}
+}
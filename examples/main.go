@@ -39,10 +39,7 @@ func scene1(context *cairo.Context, width, height, percent float64) {
 
 	s := sphere.Rotated(-percent*blmath.Tau, -percent*blmath.Tau*2, 0)
 
-	s.Stroke(1)
-
-	context.GaussianBlur(20)
-	s.Stroke(0.5)
+	s.StrokeGlow(0.5, 1, 20)
 }
 
 func scene2(context *cairo.Context, width, height, percent float64) {
@@ -57,7 +54,6 @@ func scene2(context *cairo.Context, width, height, percent float64) {
 
 	t.Stroke(1)
 	t2.Stroke(1)
-	// context.GaussianBlur(20)
-	// t.Stroke(0.5)
-	// t2.Stroke(0.5)
+	// t.StrokeGlow(0.5, 1, 20)
+	// t2.StrokeGlow(0.5, 1, 20)
 }
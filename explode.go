@@ -0,0 +1,133 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Explode breaks the shape apart, in place, moving each segment outward from the shape's centroid in a
+// slightly random direction, while spinning it around its own center. t is the progress of the explosion,
+// from 0 (intact) to 1 (each piece moved about the shape's radius away). Pieces keep the same direction, speed
+// and spin for a given seed, so calling this on a fresh copy of a shape each frame with increasing t
+// animates a shatter. Segments that shared points are given points of their own so they can fly apart,
+// and points without segments move as pieces of their own. Faces and arcs are removed.
+// Any original coordinates kept with KeepOriginal are released. Deferred transforms stay on if they were on.
+func (s *Shape) Explode(t float64, seed int64) {
+	deferred := s.frame != nil && s.original == nil
+	s.ReleaseOriginal()
+	s.DeferTransforms(deferred)
+	points := NewPointList()
+	pieces := []PointList{}
+	used := map[*Point]bool{}
+	for _, seg := range s.Segments {
+		used[seg.PointA] = true
+		used[seg.PointB] = true
+		seg.PointA = seg.PointA.Clone()
+		seg.PointB = seg.PointB.Clone()
+		points.Add(seg.PointA)
+		points.Add(seg.PointB)
+		pieces = append(pieces, PointList{seg.PointA, seg.PointB})
+	}
+	for _, p := range s.Points {
+		if !used[p] {
+			points.Add(p)
+			pieces = append(pieces, PointList{p})
+		}
+	}
+	s.Points = points
+	s.Faces = []*Face{}
+	s.Arcs = []*ArcSegment{}
+	s.Recipe = nil
+	explodePieces(s.Points, pieces, t, seed)
+}
+
+// Exploded returns a copy of this shape, broken apart. See Explode.
+func (s *Shape) Exploded(t float64, seed int64) *Shape {
+	s1 := s.Clone()
+	s1.Explode(t, seed)
+	return s1
+}
+
+// ExplodeComponents is like Explode, but each connected part of the shape, made of points joined by segments,
+// moves as a single piece. Text, for example, breaks apart into its separate strokes.
+// Nothing is disconnected, so faces and arcs are kept, moving with the points they use.
+func (s *Shape) ExplodeComponents(t float64, seed int64) {
+	s.ApplyTransform()
+	// union find, to group the points into connected components.
+	parent := map[*Point]*Point{}
+	var find func(p *Point) *Point
+	find = func(p *Point) *Point {
+		root, ok := parent[p]
+		if !ok || root == p {
+			return p
+		}
+		root = find(root)
+		parent[p] = root
+		return root
+	}
+	for _, seg := range s.Segments {
+		a, b := find(seg.PointA), find(seg.PointB)
+		if a != b {
+			parent[a] = b
+		}
+	}
+	// pieces are kept in the order of their first point, so the same shape always gets the same random values.
+	index := map[*Point]int{}
+	pieces := []PointList{}
+	for _, p := range s.Points {
+		root := find(p)
+		i, ok := index[root]
+		if !ok {
+			i = len(pieces)
+			index[root] = i
+			pieces = append(pieces, NewPointList())
+		}
+		pieces[i].Add(p)
+	}
+	explodePieces(s.Points, pieces, t, seed)
+}
+
+// explodePieces moves each piece of the points outward from their centroid, spinning it around its own center.
+func explodePieces(points PointList, pieces []PointList, t float64, seed int64) {
+	if len(points) == 0 {
+		return
+	}
+	cx, cy, cz := points.centroid()
+	radius := 0.0
+	for _, p := range points {
+		radius = math.Max(radius, math.Sqrt((p.X-cx)*(p.X-cx)+(p.Y-cy)*(p.Y-cy)+(p.Z-cz)*(p.Z-cz)))
+	}
+	rng := rand.New(rand.NewSource(seed))
+	randomDir := func() *Point {
+		// any direction, evenly spread over the sphere.
+		z := rng.Float64()*2 - 1
+		a := rng.Float64() * 2 * math.Pi
+		r := math.Sqrt(1 - z*z)
+		return NewPoint(math.Cos(a)*r, math.Sin(a)*r, z)
+	}
+
+	for _, piece := range pieces {
+		// every piece draws the same random values, whether it uses them or not, so pieces don't affect each other.
+		jitter := randomDir()
+		speed := 0.5 + rng.Float64()
+		spinAxis := randomDir()
+		spin := (rng.Float64()*2 - 1) * 2 * math.Pi
+
+		px, py, pz := piece.centroid()
+		dir := NewPoint(px-cx, py-cy, pz-cz)
+		if dir.Magnitude() == 0 {
+			dir = jitter
+		} else {
+			dir.Normalize()
+			dir.Translate(jitter.X*0.5, jitter.Y*0.5, jitter.Z*0.5)
+			dir.Normalize()
+		}
+		dist := radius * speed * t
+		for _, p := range piece {
+			p.Translate(-px, -py, -pz)
+			p.RotateAxis(spinAxis, spin*t)
+			p.Translate(px+dir.X*dist, py+dir.Y*dist, pz+dir.Z*dist)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//////////////////////////////////////////////////////////////
+// Deformations can be written as expressions, such as:
+//
+//	shape.Deform("y = y + 20 * sin(x * 0.05 + t)")
+//
+// An expression is one or more assignments to x, y or z, separated by semicolons.
+// Each is evaluated for every point, with the variables x, y and z holding the point's
+// coordinates and t holding the time passed to DeformAt. All assignments use the point's
+// coordinates from before the deformation, so "x = x * c - y * s; y = x * s + y * c"
+// rotates rather than shears.
+//
+// Operators: + - * / % ^ (power), unary minus and parentheses.
+// Constants: pi, tau, e.
+// Functions: sin, cos, tan, asin, acos, atan, atan2, sqrt, abs, exp, log, pow,
+// min, max, floor, ceil, round, sign, clamp(v, min, max), lerp(t, a, b),
+// map(v, srcMin, srcMax, dstMin, dstMax).
+//////////////////////////////////////////////////////////////
+
+// exprVars holds the variables an expression can read.
+type exprVars struct {
+	x, y, z, t float64
+}
+
+// exprNode is a compiled expression.
+type exprNode func(v *exprVars) float64
+
+// exprFunctions are the functions an expression can call, with the number of arguments they take.
+var exprFunctions = map[string]struct {
+	args int
+	f    func(a []float64) float64
+}{
+	"sin":   {1, func(a []float64) float64 { return math.Sin(a[0]) }},
+	"cos":   {1, func(a []float64) float64 { return math.Cos(a[0]) }},
+	"tan":   {1, func(a []float64) float64 { return math.Tan(a[0]) }},
+	"asin":  {1, func(a []float64) float64 { return math.Asin(a[0]) }},
+	"acos":  {1, func(a []float64) float64 { return math.Acos(a[0]) }},
+	"atan":  {1, func(a []float64) float64 { return math.Atan(a[0]) }},
+	"atan2": {2, func(a []float64) float64 { return math.Atan2(a[0], a[1]) }},
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"exp":   {1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	"log":   {1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"pow":   {2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+	"floor": {1, func(a []float64) float64 { return math.Floor(a[0]) }},
+	"ceil":  {1, func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"round": {1, func(a []float64) float64 { return math.Round(a[0]) }},
+	"sign": {1, func(a []float64) float64 {
+		if a[0] == 0 {
+			return 0
+		}
+		return math.Copysign(1, a[0])
+	}},
+	"clamp": {3, func(a []float64) float64 { return math.Max(a[1], math.Min(a[2], a[0])) }},
+	"lerp":  {3, func(a []float64) float64 { return a[1] + (a[2]-a[1])*a[0] }},
+	"map": {5, func(a []float64) float64 {
+		return a[3] + (a[0]-a[1])/(a[2]-a[1])*(a[4]-a[3])
+	}},
+}
+
+// exprConstants are the named constants an expression can use.
+var exprConstants = map[string]float64{
+	"pi":  math.Pi,
+	"tau": 2 * math.Pi,
+	"e":   math.E,
+}
+
+// Deformer is a compiled deformation expression. Compiling once with NewDeformer and applying it each frame
+// avoids parsing the expression again every time.
+type Deformer struct {
+	x, y, z exprNode
+}
+
+// NewDeformer compiles a deformation expression, such as "y = y + 20 * sin(x * 0.05 + t)".
+// See the top of expression.go for the syntax.
+func NewDeformer(expr string) (*Deformer, error) {
+	d := &Deformer{}
+	for _, statement := range strings.Split(expr, ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(statement, "=")
+		if !ok {
+			return nil, errors.New("unable to parse expression: missing = in " + strings.TrimSpace(statement))
+		}
+		node, err := parseExpr(value)
+		if err != nil {
+			return nil, errors.New("unable to parse expression: " + err.Error())
+		}
+		switch strings.TrimSpace(name) {
+		case "x":
+			d.x = node
+		case "y":
+			d.y = node
+		case "z":
+			d.z = node
+		default:
+			return nil, errors.New("unable to parse expression: can only assign to x, y or z, not " + strings.TrimSpace(name))
+		}
+	}
+	return d, nil
+}
+
+// Apply deforms a single point in place, at the given time.
+func (d *Deformer) Apply(p *Point, t float64) {
+	v := exprVars{p.X, p.Y, p.Z, t}
+	if d.x != nil {
+		p.X = d.x(&v)
+	}
+	if d.y != nil {
+		p.Y = d.y(&v)
+	}
+	if d.z != nil {
+		p.Z = d.z(&v)
+	}
+}
+
+// Deform deforms each point in this pointlist with the given deformer, at the given time.
+func (p PointList) Deform(d *Deformer, t float64) {
+	for _, point := range p {
+		d.Apply(point, t)
+	}
+}
+
+// Deform deforms the shape with an expression, such as "y = y + 20 * sin(x * 0.05)", with t set to zero.
+// Returns an error if the expression cannot be parsed, in which case the shape is unchanged.
+func (s *Shape) Deform(expr string) error {
+	return s.DeformAt(expr, 0)
+}
+
+// DeformAt deforms the shape with an expression, such as "y = y + 20 * sin(x * 0.05 + t)", at the given time.
+// Returns an error if the expression cannot be parsed, in which case the shape is unchanged.
+func (s *Shape) DeformAt(expr string, t float64) error {
+	d, err := NewDeformer(expr)
+	if err != nil {
+		return err
+	}
+	s.ApplyTransform()
+	s.Points.Deform(d, t)
+	return nil
+}
+
+// exprParser is a recursive descent parser that compiles an expression to a tree of closures.
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseExpr compiles a single arithmetic expression.
+func parseExpr(src string) (exprNode, error) {
+	tokens, err := tokenizeExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	node, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, errors.New("unexpected " + p.tokens[p.pos])
+	}
+	return node, nil
+}
+
+// tokenizeExpr splits an expression into numbers, names and single character operators.
+func tokenizeExpr(src string) ([]string, error) {
+	tokens := []string{}
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			// exponent, as in 1e-3.
+			if j < len(runes) && (runes[j] == 'e' || runes[j] == 'E') {
+				k := j + 1
+				if k < len(runes) && (runes[k] == '+' || runes[k] == '-') {
+					k++
+				}
+				if k < len(runes) && unicode.IsDigit(runes[k]) {
+					for j = k; j < len(runes) && unicode.IsDigit(runes[j]); j++ {
+					}
+				}
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case strings.ContainsRune("+-*/%^(),", r):
+			tokens = append(tokens, string(r))
+			i++
+		default:
+			return nil, errors.New("unexpected character " + string(r))
+		}
+	}
+	return tokens, nil
+}
+
+// peek returns the next token, or an empty string at the end of the expression.
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// expect consumes the given token, or returns an error if it is not next.
+func (p *exprParser) expect(token string) error {
+	if p.peek() != token {
+		if p.peek() == "" {
+			return errors.New("expected " + token + " at end of expression")
+		}
+		return errors.New("expected " + token + ", found " + p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+// sum parses additions and subtractions.
+func (p *exprParser) sum() (exprNode, error) {
+	left, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == "+" || op == "-"; op = p.peek() {
+		p.pos++
+		right, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		a, b := left, right
+		if op == "+" {
+			left = func(v *exprVars) float64 { return a(v) + b(v) }
+		} else {
+			left = func(v *exprVars) float64 { return a(v) - b(v) }
+		}
+	}
+	return left, nil
+}
+
+// product parses multiplications, divisions and remainders.
+func (p *exprParser) product() (exprNode, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == "*" || op == "/" || op == "%"; op = p.peek() {
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		a, b := left, right
+		switch op {
+		case "*":
+			left = func(v *exprVars) float64 { return a(v) * b(v) }
+		case "/":
+			left = func(v *exprVars) float64 { return a(v) / b(v) }
+		default:
+			left = func(v *exprVars) float64 { return math.Mod(a(v), b(v)) }
+		}
+	}
+	return left, nil
+}
+
+// unary parses negation.
+func (p *exprParser) unary() (exprNode, error) {
+	if p.peek() == "-" {
+		p.pos++
+		a, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(v *exprVars) float64 { return -a(v) }, nil
+	}
+	if p.peek() == "+" {
+		p.pos++
+		return p.unary()
+	}
+	return p.power()
+}
+
+// power parses exponents, which are right associative and bind tighter than negation on their left,
+// so -2^2 is -4.
+func (p *exprParser) power() (exprNode, error) {
+	base, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != "^" {
+		return base, nil
+	}
+	p.pos++
+	exp, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return func(v *exprVars) float64 { return math.Pow(base(v), exp(v)) }, nil
+}
+
+// primary parses numbers, variables, constants, function calls and parenthesized expressions.
+func (p *exprParser) primary() (exprNode, error) {
+	token := p.peek()
+	if token == "" {
+		return nil, errors.New("unexpected end of expression")
+	}
+	p.pos++
+
+	if token == "(" {
+		node, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+
+	if n, err := strconv.ParseFloat(token, 64); err == nil {
+		return func(v *exprVars) float64 { return n }, nil
+	}
+
+	switch token {
+	case "x":
+		return func(v *exprVars) float64 { return v.x }, nil
+	case "y":
+		return func(v *exprVars) float64 { return v.y }, nil
+	case "z":
+		return func(v *exprVars) float64 { return v.z }, nil
+	case "t":
+		return func(v *exprVars) float64 { return v.t }, nil
+	}
+
+	if c, ok := exprConstants[token]; ok {
+		return func(v *exprVars) float64 { return c }, nil
+	}
+
+	fn, ok := exprFunctions[token]
+	if !ok {
+		return nil, errors.New("unknown name " + token)
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	args := []exprNode{}
+	for p.peek() != ")" {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.pos++
+	if len(args) != fn.args {
+		return nil, errors.New(token + " takes " + strconv.Itoa(fn.args) + " arguments")
+	}
+	return func(v *exprVars) float64 {
+		values := make([]float64, len(args))
+		for i, arg := range args {
+			values[i] = arg(v)
+		}
+		return fn.f(values)
+	}, nil
+}
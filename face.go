@@ -0,0 +1,382 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/bit101/bitlib/blcolor"
+	"github.com/bit101/bitlib/random"
+)
+
+// Face represents a flat polygonal face made of three or more points.
+// Points are expected to be in order around the face.
+type Face struct {
+	Points PointList
+}
+
+// NewFace creates a new face from the given points.
+func NewFace(points ...*Point) *Face {
+	return &Face{points}
+}
+
+// Triangles returns the face broken into triangles, fanning out from its first point.
+// Each triangle is returned as a list of three points.
+func (f *Face) Triangles() []PointList {
+	tris := []PointList{}
+	for i := 1; i < len(f.Points)-1; i++ {
+		tris = append(tris, PointList{f.Points[0], f.Points[i], f.Points[i+1]})
+	}
+	return tris
+}
+
+// Area returns the surface area of this face.
+func (f *Face) Area() float64 {
+	area := 0.0
+	for _, tri := range f.Triangles() {
+		area += triangleArea(tri[0], tri[1], tri[2])
+	}
+	return area
+}
+
+// Normal returns the unit normal of this face, using Newell's method so that faces that are
+// not quite flat still get a sensible average. Faces wound as the shape constructors wind them,
+// such as Icosphere and Isosurface, have normals pointing outward.
+// A face with no area returns a zero length normal.
+func (f *Face) Normal() *Point {
+	x, y, z := f.newell()
+	mag := math.Sqrt(x*x + y*y + z*z)
+	if mag == 0 {
+		return NewPoint(0, 0, 0)
+	}
+	return NewPoint(x/mag, y/mag, z/mag)
+}
+
+// newell returns the Newell normal of this face. Its length is twice the area of the face.
+func (f *Face) newell() (float64, float64, float64) {
+	x, y, z := 0.0, 0.0, 0.0
+	for i, a := range f.Points {
+		b := f.Points[(i+1)%len(f.Points)]
+		x += (a.Y - b.Y) * (a.Z + b.Z)
+		y += (a.Z - b.Z) * (a.X + b.X)
+		z += (a.X - b.X) * (a.Y + b.Y)
+	}
+	return x, y, z
+}
+
+// AddFace adds a face to the shape. Does not add the face's points or edges.
+func (s *Shape) AddFace(face *Face) {
+	s.Recipe = nil
+	s.Faces = append(s.Faces, face)
+}
+
+// AddFaceByIndex adds a new face based on the indexes of the points passed.
+func (s *Shape) AddFaceByIndex(indices ...int) {
+	s.Recipe = nil
+	face := NewFace()
+	for _, i := range indices {
+		face.Points.Add(s.Points[i])
+	}
+	s.Faces = append(s.Faces, face)
+}
+
+// Fill fills each face of the shape with the given color, from back to front, so nearer faces paint over
+// farther ones. Faces are sorted by the average depth of their points, which is enough for most closed shapes,
+// but faces that cross each other, or long faces beside short ones, may overlap wrongly.
+// Faces with any point outside the near or far clipping planes are not drawn,
+// and nothing is drawn if the world's Context is not a Filler.
+func (s *Shape) Fill(color blcolor.Color) {
+	s.fillFaces(color, 0)
+}
+
+// FillStroked fills each face of the shape like Fill, then strokes its edges with the given width in the world color,
+// face by face, so nearer faces hide the edges of those behind them, for a solid wireframe look.
+// Only the edges are drawn if the world's Context is not a Filler.
+func (s *Shape) FillStroked(color blcolor.Color, width float64) {
+	s.fillFaces(color, width)
+}
+
+// fillFaces fills the faces of the shape from back to front, stroking their edges if width is more than 0.
+func (s *Shape) fillFaces(color blcolor.Color, width float64) {
+	s.ApplyTransform()
+	s.Points.project(s.boilID())
+	faces := []*Face{}
+	depths := map[*Face]float64{}
+	for _, face := range s.Faces {
+		if len(face.Points) < 3 || !face.visible() || (world.BackfaceCulling && face.backFacing()) {
+			continue
+		}
+		faces = append(faces, face)
+		_, depths[face] = face.center()
+	}
+	slices.SortStableFunc(faces, func(a, b *Face) int {
+		return cmp.Compare(depths[b], depths[a])
+	})
+	for _, face := range faces {
+		face.fill(color)
+		if width > 0 {
+			for i, p := range face.Points {
+				seg := NewSegment(p, face.Points[(i+1)%len(face.Points)])
+				seg.Stroke(width)
+			}
+		}
+	}
+}
+
+// backFacing returns whether this face is turned away from the viewer, with its normal pointing
+// away from the camera. Faces with no area are never back facing.
+func (f *Face) backFacing() bool {
+	return f.facesAwayFrom(NewPoint(0, 0, -world.CZ))
+}
+
+// facesAwayFrom returns whether this face is turned away from the given viewpoint.
+// Faces with no area never face away.
+func (f *Face) facesAwayFrom(viewpoint *Point) bool {
+	x, y, z := f.newell()
+	p := f.Points[0]
+	return x*(p.X-viewpoint.X)+y*(p.Y-viewpoint.Y)+z*(p.Z-viewpoint.Z) > 0
+}
+
+// Silhouette creates a new shape of the silhouette edges of this shape's faces, as seen from the given viewpoint:
+// the edges between a face turned toward the viewpoint and one turned away from it, which outline the shape
+// like cel shaded art. To outline the shape as it is drawn, use the camera's position, at 0, 0, minus the world's z center.
+// Edges on only one face, such as the rim of an open mesh, are not included. Points are copied.
+func (s *Shape) Silhouette(viewpoint *Point) *Shape {
+	s.ApplyTransform()
+	index := map[*Point]int{}
+	for i, p := range s.Points {
+		index[p] = i
+	}
+	type edge [2]*Point
+	// for each edge, whether it has a face turned toward the viewpoint, and one turned away.
+	front := map[edge]bool{}
+	back := map[edge]bool{}
+	edges := []edge{}
+	for _, face := range s.Faces {
+		away := face.facesAwayFrom(viewpoint)
+		for i, a := range face.Points {
+			e := edge{a, face.Points[(i+1)%len(face.Points)]}
+			if index[e[0]] > index[e[1]] {
+				e[0], e[1] = e[1], e[0]
+			}
+			if !front[e] && !back[e] {
+				edges = append(edges, e)
+			}
+			if away {
+				back[e] = true
+			} else {
+				front[e] = true
+			}
+		}
+	}
+	shape := NewShape()
+	copies := map[*Point]*Point{}
+	point := func(p *Point) *Point {
+		c, ok := copies[p]
+		if !ok {
+			c = p.Clone()
+			copies[p] = c
+			shape.AddPoint(c)
+		}
+		return c
+	}
+	for _, e := range edges {
+		if front[e] && back[e] {
+			shape.AddSegmentByPoints(point(e[0]), point(e[1]))
+		}
+	}
+	return shape
+}
+
+// frontSegments returns the segments of the shape that are not only on faces turned away from the viewer.
+func (s *Shape) frontSegments() []*Segment {
+	type edge [2]*Point
+	// an edge is back only if every face it is on is back facing.
+	back := map[edge]bool{}
+	for _, face := range s.Faces {
+		backFacing := face.backFacing()
+		for i, a := range face.Points {
+			b := face.Points[(i+1)%len(face.Points)]
+			for _, e := range []edge{{a, b}, {b, a}} {
+				if isBack, ok := back[e]; !ok || isBack {
+					back[e] = backFacing
+				}
+			}
+		}
+	}
+	segments := []*Segment{}
+	for _, seg := range s.Segments {
+		if !back[edge{seg.PointA, seg.PointB}] {
+			segments = append(segments, seg)
+		}
+	}
+	return segments
+}
+
+// visible returns whether all the points of this face are between the near and far clipping planes.
+func (f *Face) visible() bool {
+	for _, p := range f.Points {
+		if !p.Visible() {
+			return false
+		}
+	}
+	return true
+}
+
+// center returns the average y and z of the points of this face.
+func (f *Face) center() (float64, float64) {
+	y, z := 0.0, 0.0
+	for _, p := range f.Points {
+		y += p.Y
+		z += p.Z
+	}
+	return y / float64(len(f.Points)), z / float64(len(f.Points))
+}
+
+// fill fills the projected outline of this face with the given color, fogged at its center.
+// Does nothing if the world's Context is not a Filler.
+func (f *Face) fill(color blcolor.Color) {
+	filler, ok := world.Context.(Filler)
+	if !ok {
+		return
+	}
+	y, z := f.center()
+	world.Context.Save()
+	shade(&color, y, z, 1)
+	for i, p := range f.Points {
+		if i == 0 {
+			world.Context.MoveTo(snapCoord(p.Px, 0), snapCoord(p.Py, 0))
+		} else {
+			world.Context.LineTo(snapCoord(p.Px, 0), snapCoord(p.Py, 0))
+		}
+	}
+	world.Context.ClosePath()
+	filler.Fill()
+	world.Context.Restore()
+}
+
+// vertexNormals returns the unit normal at each point of the shape's faces,
+// averaged from the normals of the faces sharing the point and weighted by their areas.
+// Points that are not part of any face are not included.
+func (s *Shape) vertexNormals() map[*Point]*Point {
+	normals := map[*Point]*Point{}
+	for _, face := range s.Faces {
+		x, y, z := face.newell()
+		for _, p := range face.Points {
+			n, ok := normals[p]
+			if !ok {
+				n = NewPoint(0, 0, 0)
+				normals[p] = n
+			}
+			n.Translate(x, y, z)
+		}
+	}
+	for p, n := range normals {
+		if n.Magnitude() == 0 {
+			delete(normals, p)
+			continue
+		}
+		n.Normalize()
+	}
+	return normals
+}
+
+// SampleSurface creates a point-only shape of count random points on the faces of the given mesh.
+// Points are distributed by area, so they will be evenly spread across the surface regardless of
+// the size of individual faces.
+func SampleSurface(mesh *Shape, count int) *Shape {
+	shape := NewShape()
+	sampler := newSurfaceSampler(mesh)
+	if sampler == nil {
+		return shape
+	}
+	for range count {
+		shape.AddPoint(sampler.sample())
+	}
+	return shape
+}
+
+// SampleSurfacePoisson creates a point-only shape of random points on the faces of the given mesh,
+// where no two points are closer than minDist. This gives a more regular, blue noise distribution
+// than SampleSurface. Points are added until a large number of candidates in a row fail to fit,
+// or a million candidates have been tried in all. A minDist of 0 or less gives an empty shape.
+func SampleSurfacePoisson(mesh *Shape, minDist float64) *Shape {
+	shape := NewShape()
+	if minDist <= 0 {
+		return shape
+	}
+	sampler := newSurfaceSampler(mesh)
+	if sampler == nil {
+		return shape
+	}
+	grid := NewSpatialGrid(nil, minDist)
+	maxFailures := 1000
+	maxAttempts := 1000000
+	failures := 0
+	for attempt := 0; failures < maxFailures && attempt < maxAttempts; attempt++ {
+		p := sampler.sample()
+		if grid.Nearest(p, minDist) != nil {
+			failures++
+			continue
+		}
+		failures = 0
+		grid.Add(p)
+		shape.AddPoint(p)
+	}
+	return shape
+}
+
+// surfaceSampler picks random points on a list of triangles, weighted by area.
+type surfaceSampler struct {
+	tris  []PointList
+	areas []float64
+	total float64
+}
+
+func newSurfaceSampler(mesh *Shape) *surfaceSampler {
+	sampler := &surfaceSampler{}
+	for _, face := range mesh.Faces {
+		for _, tri := range face.Triangles() {
+			sampler.total += triangleArea(tri[0], tri[1], tri[2])
+			sampler.tris = append(sampler.tris, tri)
+			sampler.areas = append(sampler.areas, sampler.total)
+		}
+	}
+	if sampler.total == 0 {
+		return nil
+	}
+	return sampler
+}
+
+// sample returns a uniformly distributed random point on the triangles.
+func (s *surfaceSampler) sample() *Point {
+	n := random.Float() * s.total
+	index, _ := slices.BinarySearch(s.areas, n)
+	index = min(index, len(s.tris)-1)
+	tri := s.tris[index]
+
+	// https://mathworld.wolfram.com/TrianglePointPicking.html
+	u := random.Float()
+	v := random.Float()
+	if u+v > 1 {
+		u = 1 - u
+		v = 1 - v
+	}
+	a, b, c := tri[0], tri[1], tri[2]
+	return NewPoint(
+		a.X+(b.X-a.X)*u+(c.X-a.X)*v,
+		a.Y+(b.Y-a.Y)*u+(c.Y-a.Y)*v,
+		a.Z+(b.Z-a.Z)*u+(c.Z-a.Z)*v,
+	)
+}
+
+// triangleArea returns the area of the triangle formed by three points.
+func triangleArea(a, b, c *Point) float64 {
+	ux, uy, uz := b.X-a.X, b.Y-a.Y, b.Z-a.Z
+	vx, vy, vz := c.X-a.X, c.Y-a.Y, c.Z-a.Z
+	cx := uy*vz - uz*vy
+	cy := uz*vx - ux*vz
+	cz := ux*vy - uy*vx
+	return math.Sqrt(cx*cx+cy*cy+cz*cz) / 2
+}
@@ -0,0 +1,156 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// Falloff is a curve that sets how strongly a modifier affects points at different distances from it.
+// It is called with t from 0, at the center of the modifier, to 1, at the edge of its radius,
+// and returns the strength there, usually from 1 down to 0.
+type Falloff func(t float64) float64
+
+// FalloffLinear fades from full strength at the center to nothing at the edge in a straight line.
+func FalloffLinear(t float64) float64 {
+	return 1 - t
+}
+
+// FalloffSmooth fades from full strength at the center to nothing at the edge along a smoothstep curve,
+// so there are no creases where the effect begins or at its center.
+func FalloffSmooth(t float64) float64 {
+	return 1 - t*t*(3-2*t)
+}
+
+// FalloffHard is full strength everywhere within the radius, and stops abruptly at its edge.
+func FalloffHard(t float64) float64 {
+	return 1
+}
+
+// Force attracts points within its radius toward its center, or with a negative strength, pushes them away.
+// A point at the center feels the force's full strength, fading to nothing at the radius along the falloff curve.
+// Strength is measured in radii: a point feeling a strength of 1 moves the length of the radius,
+// though attraction never moves a point past the center.
+type Force struct {
+	Center   *Point
+	Radius   float64
+	Strength float64
+	Falloff  Falloff
+}
+
+// NewForce creates a new force with a linear falloff.
+func NewForce(center *Point, radius, strength float64) *Force {
+	return &Force{center, radius, strength, FalloffLinear}
+}
+
+// offset returns how far the force moves the given point.
+func (f *Force) offset(p *Point) (float64, float64, float64) {
+	dx, dy, dz := f.Center.X-p.X, f.Center.Y-p.Y, f.Center.Z-p.Z
+	dist := math.Sqrt(dx*dx + dy*dy + dz*dz)
+	if dist >= f.Radius || dist == 0 {
+		return 0, 0, 0
+	}
+	falloff := f.Falloff
+	if falloff == nil {
+		falloff = FalloffLinear
+	}
+	move := f.Strength * falloff(dist/f.Radius) * f.Radius
+	move = math.Min(move, dist)
+	return dx / dist * move, dy / dist * move, dz / dist * move
+}
+
+// ApplyForces moves each point in this pointlist by the combined effect of all the forces, in place.
+// Each point's movement is worked out from where it started, so the order of the forces and points doesn't matter.
+func (p PointList) ApplyForces(forces []*Force) {
+	for _, point := range p {
+		x, y, z := 0.0, 0.0, 0.0
+		for _, f := range forces {
+			dx, dy, dz := f.offset(point)
+			x, y, z = x+dx, y+dy, z+dz
+		}
+		point.Translate(x, y, z)
+	}
+}
+
+// Attract pulls points within radius toward the attractor, the inverse of Push. Points at the attractor
+// move by strength times the radius, fading linearly to no movement at the radius. See Force.
+func (p PointList) Attract(attractor *Point, radius, strength float64) {
+	p.ApplyForces([]*Force{NewForce(attractor, radius, strength)})
+}
+
+// ApplyForces moves each point of the shape by the combined effect of all the forces, in place.
+// See PointList.ApplyForces.
+func (s *Shape) ApplyForces(forces []*Force) {
+	s.ApplyTransform()
+	s.Points.ApplyForces(forces)
+}
+
+// Attract pulls points of the shape within radius toward the attractor. See PointList.Attract.
+func (s *Shape) Attract(attractor *Point, radius, strength float64) {
+	s.ApplyTransform()
+	s.Points.Attract(attractor, radius, strength)
+}
+
+// Pusher is anything points can be pushed away from by PushWith. ClosestPoint returns a new point,
+// on the pusher, as close as possible to the given point. Points, segments and planes are all pushers.
+type Pusher interface {
+	ClosestPoint(p *Point) *Point
+}
+
+// ClosestPoint returns a copy of this point, which is the only point on it, so a point can be used as a Pusher.
+func (p *Point) ClosestPoint(other *Point) *Point {
+	return p.Clone()
+}
+
+// Plane is an infinite flat plane, through a point and facing along a normal.
+type Plane struct {
+	Point  *Point
+	Normal *Point
+}
+
+// NewPlane creates a new plane through the given point. The normal does not need to be normalized.
+func NewPlane(point, normal *Point) *Plane {
+	return &Plane{point, normal.Normalized()}
+}
+
+// ClosestPoint returns a new point on this plane, as close as possible to the given point.
+func (pl *Plane) ClosestPoint(p *Point) *Point {
+	n := pl.Normal
+	d := (p.X-pl.Point.X)*n.X + (p.Y-pl.Point.Y)*n.Y + (p.Z-pl.Point.Z)*n.Z
+	return NewPoint(p.X-d*n.X, p.Y-d*n.Y, p.Z-d*n.Z)
+}
+
+// PushWith pushes points within radius of the pusher away from it, in place. A point is pushed directly
+// away from the closest point on the pusher, so a segment pusher dents a shape like a wand and a plane pusher
+// carves a flat-bottomed trench through it. The falloff sets how far toward the edge of the radius each point goes:
+// with FalloffHard, every point is moved all the way out, leaving a clean hole, while smoother falloffs leave
+// a softer dent. Points exactly on the pusher have no direction to move in, so they stay where they are.
+func (p PointList) PushWith(pusher Pusher, radius float64, falloff Falloff) {
+	if falloff == nil {
+		falloff = FalloffHard
+	}
+	for _, point := range p {
+		closest := pusher.ClosestPoint(point)
+		dist := point.Distance(closest)
+		if dist >= radius || dist == 0 {
+			continue
+		}
+		newDist := dist + (radius-dist)*falloff(dist/radius)
+		point.X -= closest.X
+		point.Y -= closest.Y
+		point.Z -= closest.Z
+		point.UniScale(newDist / dist)
+		point.X += closest.X
+		point.Y += closest.Y
+		point.Z += closest.Z
+	}
+}
+
+// Push pushes points of the shape within radius of the pusher out to the edge of the radius. See PointList.Push.
+func (s *Shape) Push(pusher *Point, radius float64) {
+	s.ApplyTransform()
+	s.Points.Push(pusher, radius)
+}
+
+// PushWith pushes points of the shape within radius of the pusher away from it. See PointList.PushWith.
+func (s *Shape) PushWith(pusher Pusher, radius float64, falloff Falloff) {
+	s.ApplyTransform()
+	s.Points.PushWith(pusher, radius, falloff)
+}
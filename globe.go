@@ -0,0 +1,111 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math"
+
+	"github.com/bit101/bitlib/blmath"
+)
+
+// globeCoord is the position of a point on a globe: its latitude and longitude in radians, and distance from the center.
+type globeCoord struct {
+	lat, lon, r float64
+}
+
+// globeCoordOf returns the position on a globe of a point, using the same orientation as Graticule.
+func globeCoordOf(p *Point) globeCoord {
+	r := p.Magnitude()
+	if r == 0 {
+		return globeCoord{}
+	}
+	return globeCoord{math.Asin(blmath.Clamp(-p.Y/r, -1, 1)), math.Atan2(p.X, -p.Z), r}
+}
+
+// atPole returns whether the coordinate is at one of the poles, where its longitude has no meaning.
+func (g globeCoord) atPole() bool {
+	return math.Cos(g.lat) < 1e-9
+}
+
+// globeToMapPoint returns where a point at the given globe coordinate lands, part way through morphing
+// a globe of the given radius into a flat map.
+func globeToMapPoint(g globeCoord, radius, t float64) *Point {
+	// the globe is unrolled by growing its radius while shrinking its angles, so distances on its surface stay the same
+	// and the point at latitude and longitude 0 stays put. at full size, it is flat.
+	s := 1 - blmath.Clamp(t, 0, 1)
+	elevation := g.r - radius
+	if s < 1e-6 {
+		return NewPoint(g.lon*radius, -g.lat*radius, -radius-elevation)
+	}
+	big := radius / s
+	p := latLonPoint(g.lat*s, g.lon*s, big+elevation)
+	p.TranslateZ(big - radius)
+	return p
+}
+
+// GlobeToMap morphs a shape lying on a globe of the given radius, centered on the origin, into a flat
+// equirectangular map, in place. t is the progress of the morph, from 0 (the globe) to 1 (the map).
+// The shape uses the same orientation as Graticule, and could be a graticule, coastlines, or anything else.
+// Points off the surface keep their height above it, which becomes distance toward the viewer on the map.
+//
+// The globe unrolls smoothly from its front: the point at latitude and longitude 0 stays put,
+// and the map lies on the plane z = -radius, with longitude across the x-axis and latitude up the y-axis,
+// at one unit of radius per radian. The map is cut along longitude 180. Segments crossing that line are split
+// in two at it, and segments meeting at the poles get points of their own, so they spread out along the top
+// and bottom of the map. Faces and arcs are not split, so faces across the cut stretch across the map.
+// Call it on a fresh copy of the globe each frame to animate the morph.
+func (s *Shape) GlobeToMap(radius, t float64) {
+	s.ApplyTransform()
+	coords := map[*Point]globeCoord{}
+	for _, p := range s.Points {
+		coords[p] = globeCoordOf(p)
+	}
+	addPoint := func(g globeCoord) *Point {
+		p := NewPoint(0, 0, 0)
+		coords[p] = g
+		s.AddPoint(p)
+		return p
+	}
+
+	segments := []*Segment{}
+	for _, seg := range s.Segments {
+		a, b := seg.PointA, seg.PointB
+		ga, gb := coords[a], coords[b]
+		if ga.atPole() && !gb.atPole() {
+			ga.lon = gb.lon
+			a = addPoint(ga)
+		}
+		if gb.atPole() && !ga.atPole() {
+			gb.lon = ga.lon
+			b = addPoint(gb)
+		}
+		if math.Abs(gb.lon-ga.lon) <= math.Pi {
+			segments = append(segments, &Segment{a, b, seg.Width})
+			continue
+		}
+		// the segment crosses longitude 180, so it is split where it crosses, with a point on each edge of the map.
+		edge := math.Copysign(math.Pi, ga.lon)
+		lonB := gb.lon + 2*edge
+		f := (edge - ga.lon) / (lonB - ga.lon)
+		lat, r := ga.lat+(gb.lat-ga.lat)*f, ga.r+(gb.r-ga.r)*f
+		c1 := addPoint(globeCoord{lat, edge, r})
+		c2 := addPoint(globeCoord{lat, -edge, r})
+		segments = append(segments, &Segment{a, c1, seg.Width}, &Segment{c2, b, seg.Width})
+	}
+	s.Segments = segments
+	s.Recipe = nil
+
+	for _, p := range s.Points {
+		m := globeToMapPoint(coords[p], radius, t)
+		p.X, p.Y, p.Z = m.X, m.Y, m.Z
+	}
+}
+
+// GlobeToMapMorph creates a globe of latitude and longitude lines, every 15 degrees, with a radius of 1,
+// part way through morphing into a flat equirectangular map, from 0 (the globe) to 1 (the map). See GlobeToMap.
+// UniScale it to the size needed.
+func GlobeToMapMorph(t float64) *Shape {
+	step := math.Pi / 12
+	shape := Graticule(1, step, step)
+	shape.GlobeToMap(1, t)
+	return shape
+}
@@ -0,0 +1,101 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// ShapeFromGraph creates a shape from a list of nodes and a list of edges connecting them by index.
+// Does not clone the nodes, so transforms to the new shape will affect the original points as well.
+// If the nodes don't have meaningful positions yet, use LayoutForceDirected to arrange them.
+func ShapeFromGraph(nodes []*Point, edges [][2]int) *Shape {
+	shape := NewShape()
+	shape.Points = append(shape.Points, nodes...)
+	for _, e := range edges {
+		shape.AddSegmentByIndex(e[0], e[1])
+	}
+	return shape
+}
+
+// LayoutForceDirected arranges the points of a shape as a 3d force directed graph, in place.
+// Every point pushes every other point away, while segments pull the points they connect together,
+// so connected clusters group together and the whole graph spreads out evenly.
+// Uses the Fruchterman-Reingold algorithm, which is O(N^2) per iteration. 100 to 500 iterations is typical.
+// If all the points start in the same place, they are first scattered randomly.
+func (s *Shape) LayoutForceDirected(iterations int) {
+	s.ApplyTransform()
+	points := s.Points
+	n := len(points)
+	if n < 2 || iterations < 1 {
+		return
+	}
+
+	// k is the ideal distance between points, based on the space they take up.
+	w, h, d := points.GetSize()
+	if w == 0 && h == 0 && d == 0 {
+		points.Randomize(50 * math.Cbrt(float64(n)))
+		w, h, d = points.GetSize()
+	}
+	size := math.Max(w, math.Max(h, d))
+	k := size / math.Cbrt(float64(n))
+	temp := size / 10
+
+	dx := make([]float64, n)
+	dy := make([]float64, n)
+	dz := make([]float64, n)
+	indices := map[*Point]int{}
+	for i, p := range points {
+		indices[p] = i
+	}
+
+	for iter := range iterations {
+		clear(dx)
+		clear(dy)
+		clear(dz)
+
+		// repulsion between all pairs
+		for i := 0; i < n-1; i++ {
+			for j := i + 1; j < n; j++ {
+				x := points[i].X - points[j].X
+				y := points[i].Y - points[j].Y
+				z := points[i].Z - points[j].Z
+				dist := math.Max(math.Sqrt(x*x+y*y+z*z), 0.01)
+				force := k * k / dist / dist
+				dx[i] += x * force
+				dy[i] += y * force
+				dz[i] += z * force
+				dx[j] -= x * force
+				dy[j] -= y * force
+				dz[j] -= z * force
+			}
+		}
+
+		// attraction along segments
+		for _, seg := range s.Segments {
+			i, okA := indices[seg.PointA]
+			j, okB := indices[seg.PointB]
+			if !okA || !okB {
+				continue
+			}
+			x := points[i].X - points[j].X
+			y := points[i].Y - points[j].Y
+			z := points[i].Z - points[j].Z
+			dist := math.Sqrt(x*x + y*y + z*z)
+			force := dist / k
+			dx[i] -= x * force
+			dy[i] -= y * force
+			dz[i] -= z * force
+			dx[j] += x * force
+			dy[j] += y * force
+			dz[j] += z * force
+		}
+
+		// move each point, limited by the temperature, which cools over time.
+		t := temp * (1 - float64(iter)/float64(iterations))
+		for i, p := range points {
+			mag := math.Sqrt(dx[i]*dx[i] + dy[i]*dy[i] + dz[i]*dz[i])
+			if mag > 0 {
+				step := math.Min(mag, t) / mag
+				p.Translate(dx[i]*step, dy[i]*step, dz[i]*step)
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// hullFace is a triangular face of a convex hull, referencing points by index.
+// Points are ordered counterclockwise when viewed from outside the hull.
+type hullFace struct {
+	a, b, c    int
+	nx, ny, nz float64
+	d          float64
+}
+
+func newHullFace(points PointList, a, b, c int) *hullFace {
+	pa, pb, pc := points[a], points[b], points[c]
+	ux, uy, uz := pb.X-pa.X, pb.Y-pa.Y, pb.Z-pa.Z
+	vx, vy, vz := pc.X-pa.X, pc.Y-pa.Y, pc.Z-pa.Z
+	nx := uy*vz - uz*vy
+	ny := uz*vx - ux*vz
+	nz := ux*vy - uy*vx
+	mag := math.Sqrt(nx*nx + ny*ny + nz*nz)
+	if mag > 0 {
+		nx, ny, nz = nx/mag, ny/mag, nz/mag
+	}
+	return &hullFace{a, b, c, nx, ny, nz, nx*pa.X + ny*pa.Y + nz*pa.Z}
+}
+
+// distance returns the signed distance of a point from the plane of this face. Positive is outside.
+func (f *hullFace) distance(p *Point) float64 {
+	return f.nx*p.X + f.ny*p.Y + f.nz*p.Z - f.d
+}
+
+// convexHull computes the 3d convex hull of a list of points using the incremental algorithm.
+// It returns the triangular faces of the hull. If the points are all coplanar or there are fewer
+// than four of them, no faces are returned.
+func convexHull(points PointList) []*hullFace {
+	if len(points) < 4 {
+		return nil
+	}
+	w, h, d := points.GetSize()
+	eps := math.Max(w, math.Max(h, d)) * 1e-9
+
+	// initial tetrahedron from four non-coplanar points.
+	i0 := 0
+	i1 := -1
+	for i := 1; i < len(points); i++ {
+		if points[i].Distance(points[i0]) > eps {
+			i1 = i
+			break
+		}
+	}
+	if i1 < 0 {
+		return nil
+	}
+	i2 := -1
+	for i := i1 + 1; i < len(points); i++ {
+		if triangleArea(points[i0], points[i1], points[i]) > eps*eps {
+			i2 = i
+			break
+		}
+	}
+	if i2 < 0 {
+		return nil
+	}
+	base := newHullFace(points, i0, i1, i2)
+	i3 := -1
+	for i := i2 + 1; i < len(points); i++ {
+		if math.Abs(base.distance(points[i])) > eps {
+			i3 = i
+			break
+		}
+	}
+	if i3 < 0 {
+		return nil
+	}
+	if base.distance(points[i3]) > 0 {
+		i1, i2 = i2, i1
+	}
+	faces := []*hullFace{
+		newHullFace(points, i0, i1, i2),
+		newHullFace(points, i0, i3, i1),
+		newHullFace(points, i1, i3, i2),
+		newHullFace(points, i2, i3, i0),
+	}
+
+	// add each remaining point, replacing the faces it can see with a fan of faces
+	// connecting it to the horizon.
+	for i, p := range points {
+		if i == i0 || i == i1 || i == i2 || i == i3 {
+			continue
+		}
+		// edges are kept in a slice as well as the map, so new faces are added in the same order every time.
+		visible := map[[2]int]bool{}
+		edges := [][2]int{}
+		kept := []*hullFace{}
+		for _, f := range faces {
+			if f.distance(p) > eps {
+				for _, edge := range [][2]int{{f.a, f.b}, {f.b, f.c}, {f.c, f.a}} {
+					visible[edge] = true
+					edges = append(edges, edge)
+				}
+			} else {
+				kept = append(kept, f)
+			}
+		}
+		if len(visible) == 0 {
+			continue
+		}
+		for _, edge := range edges {
+			if !visible[[2]int{edge[1], edge[0]}] {
+				kept = append(kept, newHullFace(points, edge[0], edge[1], i))
+			}
+		}
+		faces = kept
+	}
+	return faces
+}
+
+// ConvexHull creates a new shape that tightly wraps the given points in a convex wireframe shell.
+// The shape contains copies of the points on the hull, each triangular face of the hull, and
+// the edges of those faces as segments.
+func ConvexHull(points PointList) *Shape {
+	shape := NewShape()
+	indices := map[int]int{}
+	index := func(i int) int {
+		if j, ok := indices[i]; ok {
+			return j
+		}
+		shape.AddPoint(points[i].Clone())
+		indices[i] = len(shape.Points) - 1
+		return indices[i]
+	}
+	edges := map[[2]int]bool{}
+	for _, f := range convexHull(points) {
+		a, b, c := index(f.a), index(f.b), index(f.c)
+		shape.AddFaceByIndex(a, b, c)
+		for _, e := range [][2]int{{a, b}, {b, c}, {c, a}} {
+			key := [2]int{min(e[0], e[1]), max(e[0], e[1])}
+			if !edges[key] {
+				edges[key] = true
+				shape.AddSegmentByIndex(e[0], e[1])
+			}
+		}
+	}
+	return shape
+}
@@ -0,0 +1,134 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/bit101/bitlib/blmath"
+)
+
+// Instrument shapes lie on the xy plane, facing the viewer, with a radius of 100.
+// Use UniScale or Scale to resize them. Labels use the current font type,
+// but are sized to fit the instrument rather than by the current font size.
+// Angles are measured clockwise from the top, like the hands of a clock.
+
+const instrumentRadius = 100.0
+
+// Gauge creates a meter with a 270 degree scale from min to max, and a needle pointing at value.
+// The scale has five labeled major divisions, each split into five minor ones.
+// The needle stops at the ends of the scale if value is out of range.
+func Gauge(value, min, max float64) *Shape {
+	shape := NewShape()
+	sweep := blmath.DegToRad(270)
+	start := -sweep / 2
+	instrumentArc(shape, instrumentRadius, start, start+sweep)
+	divisions := 25
+	for i := 0; i <= divisions; i++ {
+		t := float64(i) / float64(divisions)
+		angle := start + sweep*t
+		if i%5 == 0 {
+			instrumentTick(shape, angle, 0.8, 1)
+			text := fmt.Sprintf("%.3g", blmath.Lerp(t, min, max))
+			shape.AddShape(instrumentLabel(text, angle, 0.6, 12))
+		} else {
+			instrumentTick(shape, angle, 0.9, 1)
+		}
+	}
+	t := 0.0
+	if max != min {
+		t = blmath.Clamp((value-min)/(max-min), 0, 1)
+	}
+	instrumentHand(shape, start+sweep*t, 0.85)
+	return shape
+}
+
+// ClockFace creates an analog clock face with hour, minute and second hands showing the given time.
+// The face has numerals from 1 to 12, a long tick at each hour and a short one at each minute.
+// The hands move smoothly, so the hour hand sits part way between hours.
+func ClockFace(t time.Time) *Shape {
+	shape := NewShape()
+	instrumentArc(shape, instrumentRadius, 0, blmath.Tau)
+	for i := range 60 {
+		angle := blmath.Tau * float64(i) / 60
+		if i%5 == 0 {
+			instrumentTick(shape, angle, 0.85, 1)
+			shape.AddShape(instrumentLabel(fmt.Sprint((i/5+11)%12+1), angle, 0.7, 12))
+		} else {
+			instrumentTick(shape, angle, 0.93, 1)
+		}
+	}
+	seconds := float64(t.Second()) + float64(t.Nanosecond())/1e9
+	minutes := float64(t.Minute()) + seconds/60
+	hours := float64(t.Hour()%12) + minutes/60
+	instrumentHand(shape, blmath.Tau*hours/12, 0.45)
+	instrumentHand(shape, blmath.Tau*minutes/60, 0.75)
+	instrumentHand(shape, blmath.Tau*seconds/60, 0.9)
+	return shape
+}
+
+// Dial creates a round dial with the given number of ticks evenly spaced around it,
+// each labeled with its number, starting from 0 at the top.
+func Dial(ticks int) *Shape {
+	shape := NewShape()
+	instrumentArc(shape, instrumentRadius, 0, blmath.Tau)
+	instrumentArc(shape, instrumentRadius*0.8, 0, blmath.Tau)
+	for i := range ticks {
+		angle := blmath.Tau * float64(i) / float64(ticks)
+		instrumentTick(shape, angle, 0.8, 1)
+		shape.AddShape(instrumentLabel(fmt.Sprint(i), angle, 0.65, 10))
+	}
+	return shape
+}
+
+// instrumentPoint creates a point at the given angle, clockwise from the top, and radius.
+func instrumentPoint(angle, radius float64) *Point {
+	return NewPoint(math.Sin(angle)*radius, -math.Cos(angle)*radius, 0)
+}
+
+// instrumentArc adds an arc of the given radius to the shape, between two angles.
+// A full circle is closed rather than doubling its first point.
+func instrumentArc(shape *Shape, radius, start, end float64) {
+	full := math.Abs(end-start) >= blmath.Tau
+	count := max(1, int(math.Ceil(math.Abs(end-start)/blmath.Tau*72)))
+	first := instrumentPoint(start, radius)
+	shape.AddPoint(first)
+	prev := first
+	for i := 1; i <= count; i++ {
+		if full && i == count {
+			shape.AddSegmentByPoints(prev, first)
+			break
+		}
+		p := instrumentPoint(blmath.Lerp(float64(i)/float64(count), start, end), radius)
+		shape.AddPoint(p)
+		shape.AddSegmentByPoints(prev, p)
+		prev = p
+	}
+}
+
+// instrumentTick adds a radial tick mark at the given angle to the shape,
+// running between two fractions of the instrument radius.
+func instrumentTick(shape *Shape, angle, inner, outer float64) {
+	a := instrumentPoint(angle, inner*instrumentRadius)
+	b := instrumentPoint(angle, outer*instrumentRadius)
+	shape.AddPoint(a)
+	shape.AddPoint(b)
+	shape.AddSegmentByPoints(a, b)
+}
+
+// instrumentHand adds a hand or needle from the center of the instrument to the shape,
+// pointing at the given angle, with its length a fraction of the instrument radius.
+func instrumentHand(shape *Shape, angle, length float64) {
+	instrumentTick(shape, angle, 0, length)
+}
+
+// instrumentLabel creates a line of text centered at the given angle and fraction of the instrument radius,
+// with each letter the given width.
+func instrumentLabel(text string, angle, radius, size float64) *Shape {
+	label := NewString(text).AsLine()
+	label.UniScale(size / world.FontSize)
+	p := instrumentPoint(angle, radius*instrumentRadius)
+	label.Translate(p.X, p.Y, 0)
+	return label
+}
@@ -0,0 +1,226 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"slices"
+	"sync"
+)
+
+// Isosurface creates a wireframe of the surface where the function f is equal to level, within the given bounds,
+// using marching cubes. The bounds are divided into res cubes on each axis.
+// Points where f is greater than level are considered inside the surface.
+// The shape's points are welded, so neighboring triangles share points, and each triangle is added as a face,
+// with its normal pointing outward. Each triangle edge is added once as a segment.
+// This can be used for metaballs, gyroids, signed distance functions and other implicit surfaces.
+// The field is sampled in parallel when Parallel is set higher than 1, so f must then be safe for concurrent use.
+func Isosurface(f func(x, y, z float64) float64, bounds Bounds, res int, level float64) *Shape {
+	shape := NewShape()
+	if res < 1 {
+		return shape
+	}
+	w, h, d := bounds.Size()
+	dx, dy, dz := w/float64(res), h/float64(res), d/float64(res)
+
+	// sample the field once at each grid point.
+	n := res + 1
+	values := make([]float64, n*n*n)
+	parallelFor(n, func(z int) {
+		for y := range n {
+			for x := range n {
+				values[(z*n+y)*n+x] = f(
+					bounds.MinX+float64(x)*dx,
+					bounds.MinY+float64(y)*dy,
+					bounds.MinZ+float64(z)*dz,
+				)
+			}
+		}
+	})
+
+	// surface points are created on grid edges, keyed by the grid point the edge starts at, and its axis.
+	// neighboring cubes share these, so the surface is welded.
+	edgePoints := map[[4]int]int{}
+	pointOnEdge := func(x, y, z, e int) int {
+		a, b := mcEdges[e][0], mcEdges[e][1]
+		ax, ay, az := x+a&1, y+a>>1&1, z+a>>2&1
+		bx, by, bz := x+b&1, y+b>>1&1, z+b>>2&1
+		key := [4]int{ax, ay, az, bx - ax + (by-ay)*2 + (bz-az)*4}
+		if index, ok := edgePoints[key]; ok {
+			return index
+		}
+		va := values[(az*n+ay)*n+ax]
+		vb := values[(bz*n+by)*n+bx]
+		t := (level - va) / (vb - va)
+		shape.AddXYZ(
+			bounds.MinX+(float64(ax)+float64(bx-ax)*t)*dx,
+			bounds.MinY+(float64(ay)+float64(by-ay)*t)*dy,
+			bounds.MinZ+(float64(az)+float64(bz-az)*t)*dz,
+		)
+		edgePoints[key] = len(shape.Points) - 1
+		return edgePoints[key]
+	}
+
+	table := marchingCubesTable()
+	segments := map[[2]int]bool{}
+	addSegment := func(i, j int) {
+		key := [2]int{min(i, j), max(i, j)}
+		if !segments[key] {
+			segments[key] = true
+			shape.AddSegmentByIndex(i, j)
+		}
+	}
+	for z := range res {
+		for y := range res {
+			for x := range res {
+				config := 0
+				for c := range 8 {
+					if values[((z+c>>2&1)*n+y+c>>1&1)*n+x+c&1] > level {
+						config |= 1 << c
+					}
+				}
+				for _, tri := range table[config] {
+					a := pointOnEdge(x, y, z, tri[0])
+					b := pointOnEdge(x, y, z, tri[1])
+					c := pointOnEdge(x, y, z, tri[2])
+					shape.AddFaceByIndex(a, b, c)
+					addSegment(a, b)
+					addSegment(b, c)
+					addSegment(c, a)
+				}
+			}
+		}
+	}
+	return shape
+}
+
+//////////////////////////////
+// Marching cubes table
+//////////////////////////////
+
+// Cube corners are numbered so that bit 0 is x, bit 1 is y and bit 2 is z.
+// Rather than hard coding the classic 256 case triangle table, it is built on first use
+// by tracing where the surface crosses each face of the cube.
+
+// mcEdges are the 12 edges of a cube, as pairs of corners.
+var mcEdges = [12][2]int{
+	{0, 1}, {2, 3}, {4, 5}, {6, 7},
+	{0, 2}, {1, 3}, {4, 6}, {5, 7},
+	{0, 4}, {1, 5}, {2, 6}, {3, 7},
+}
+
+var (
+	mcTable     [256][][3]int
+	mcTableOnce sync.Once
+)
+
+// marchingCubesTable returns the triangles, as triples of cube edges, for each of the 256 cube configurations.
+func marchingCubesTable() *[256][][3]int {
+	mcTableOnce.Do(func() {
+		edgeIndex := map[[2]int]int{}
+		for i, e := range mcEdges {
+			edgeIndex[e] = i
+			edgeIndex[[2]int{e[1], e[0]}] = i
+		}
+
+		// the six faces, each as four corners in order around the face.
+		faces := [][4]int{}
+		for _, axis := range []int{1, 2, 4} {
+			u, v := 1, 2
+			if axis == 1 {
+				u, v = 2, 4
+			} else if axis == 2 {
+				u, v = 1, 4
+			}
+			for _, side := range []int{0, axis} {
+				faces = append(faces, [4]int{side, side + u, side + u + v, side + v})
+			}
+		}
+
+		for config := range 256 {
+			inside := func(c int) bool { return config&(1<<c) != 0 }
+			links := map[int][]int{}
+			link := func(a, b int) {
+				links[a] = append(links[a], b)
+				links[b] = append(links[b], a)
+			}
+			for _, q := range faces {
+				crossings := []int{}
+				for k := range 4 {
+					if inside(q[k]) != inside(q[(k+1)%4]) {
+						crossings = append(crossings, edgeIndex[[2]int{q[k], q[(k+1)%4]}])
+					}
+				}
+				if len(crossings) == 2 {
+					link(crossings[0], crossings[1])
+				} else if len(crossings) == 4 {
+					// ambiguous face with diagonal corners inside. always separate the inside corners.
+					// this depends only on the face itself, so neighboring cubes agree.
+					if inside(q[0]) {
+						link(crossings[3], crossings[0])
+						link(crossings[1], crossings[2])
+					} else {
+						link(crossings[0], crossings[1])
+						link(crossings[2], crossings[3])
+					}
+				}
+			}
+
+			// every crossed edge is linked to exactly two others, forming closed loops. fan each loop into triangles.
+			visited := map[int]bool{}
+			for e := range 12 {
+				if visited[e] || len(links[e]) == 0 {
+					continue
+				}
+				loop := []int{e}
+				visited[e] = true
+				prev, cur := e, links[e][0]
+				for cur != e {
+					loop = append(loop, cur)
+					visited[cur] = true
+					next := links[cur][0]
+					if next == prev {
+						next = links[cur][1]
+					}
+					prev, cur = cur, next
+				}
+				if !mcFacesOut(config, loop) {
+					slices.Reverse(loop)
+				}
+				for i := 1; i < len(loop)-1; i++ {
+					mcTable[config] = append(mcTable[config], [3]int{loop[0], loop[i], loop[i+1]})
+				}
+			}
+		}
+	})
+	return &mcTable
+}
+
+// mcFacesOut returns whether the normal of a loop of edges points away from the inside corner
+// of its first edge, towards the outside of the surface.
+func mcFacesOut(config int, loop []int) bool {
+	corner := func(c int) (float64, float64, float64) {
+		return float64(c & 1), float64(c >> 1 & 1), float64(c >> 2 & 1)
+	}
+	mid := func(e int) (float64, float64, float64) {
+		ax, ay, az := corner(mcEdges[e][0])
+		bx, by, bz := corner(mcEdges[e][1])
+		return (ax + bx) / 2, (ay + by) / 2, (az + bz) / 2
+	}
+
+	// Newell's method, for the normal of a polygon that may not be quite flat.
+	nx, ny, nz := 0.0, 0.0, 0.0
+	for i, e := range loop {
+		ax, ay, az := mid(e)
+		bx, by, bz := mid(loop[(i+1)%len(loop)])
+		nx += (ay - by) * (az + bz)
+		ny += (az - bz) * (ax + bx)
+		nz += (ax - bx) * (ay + by)
+	}
+
+	in, out := mcEdges[loop[0]][0], mcEdges[loop[0]][1]
+	if config&(1<<in) == 0 {
+		in, out = out, in
+	}
+	ix, iy, iz := corner(in)
+	ox, oy, oz := corner(out)
+	return nx*(ox-ix)+ny*(oy-iy)+nz*(oz-iz) > 0
+}
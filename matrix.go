@@ -0,0 +1,165 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+// Matrix is a 4x4 affine transform matrix, stored in row major order.
+// Points are transformed as column vectors, so the last column holds the translation.
+//
+// A Matrix is built by chaining transforms, starting with IdentityMatrix:
+//
+//	m := wire.IdentityMatrix().RotateY(angle).Translate(0, 0, 500)
+//
+// Each call adds a transform that happens after the ones before it, exactly as if the same methods
+// were called on a shape one after another. Transforming a point list by the result then moves
+// each point only once, however long the chain.
+type Matrix [16]float64
+
+// IdentityMatrix returns a matrix that does not change points.
+func IdentityMatrix() Matrix {
+	return Matrix{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// matrixFrom returns the matrix equivalent of an affine point transform, such as a rotation.
+func matrixFrom(f func(p *Point)) Matrix {
+	o := NewPoint(0, 0, 0)
+	ax := NewPoint(1, 0, 0)
+	ay := NewPoint(0, 1, 0)
+	az := NewPoint(0, 0, 1)
+	frame := PointList{o, ax, ay, az}
+	for _, p := range frame {
+		f(p)
+	}
+	return frameMatrix(frame)
+}
+
+// frameMatrix returns the matrix that moves the origin and the ends of the three unit axes
+// to the four points of the given frame.
+// The columns of the matrix are the transformed axes, relative to the transformed origin.
+func frameMatrix(frame PointList) Matrix {
+	o, ax, ay, az := frame[0], frame[1], frame[2], frame[3]
+	return Matrix{
+		ax.X - o.X, ay.X - o.X, az.X - o.X, o.X,
+		ax.Y - o.Y, ay.Y - o.Y, az.Y - o.Y, o.Y,
+		ax.Z - o.Z, ay.Z - o.Z, az.Z - o.Z, o.Z,
+		0, 0, 0, 1,
+	}
+}
+
+// Multiply returns the product of this matrix and another.
+// Transforming by the result is the same as transforming by n, then by m.
+func (m Matrix) Multiply(n Matrix) Matrix {
+	var r Matrix
+	for row := range 4 {
+		for col := range 4 {
+			sum := 0.0
+			for i := range 4 {
+				sum += m[row*4+i] * n[i*4+col]
+			}
+			r[row*4+col] = sum
+		}
+	}
+	return r
+}
+
+// then returns this matrix followed by the given point transform.
+func (m Matrix) then(f func(p *Point)) Matrix {
+	return matrixFrom(f).Multiply(m)
+}
+
+// Translate returns this matrix followed by a translation on all axes.
+func (m Matrix) Translate(tx, ty, tz float64) Matrix {
+	return m.then(func(p *Point) { p.Translate(tx, ty, tz) })
+}
+
+// RotateX returns this matrix followed by a rotation around the x-axis.
+func (m Matrix) RotateX(angle float64) Matrix {
+	return m.then(func(p *Point) { p.RotateX(angle) })
+}
+
+// RotateY returns this matrix followed by a rotation around the y-axis.
+func (m Matrix) RotateY(angle float64) Matrix {
+	return m.then(func(p *Point) { p.RotateY(angle) })
+}
+
+// RotateZ returns this matrix followed by a rotation around the z-axis.
+func (m Matrix) RotateZ(angle float64) Matrix {
+	return m.then(func(p *Point) { p.RotateZ(angle) })
+}
+
+// Rotate returns this matrix followed by rotations around all axes.
+func (m Matrix) Rotate(rx, ry, rz float64) Matrix {
+	return m.then(func(p *Point) { p.Rotate(rx, ry, rz) })
+}
+
+// RotateAxis returns this matrix followed by a rotation around an arbitrary axis through the origin.
+// See Point.RotateAxis.
+func (m Matrix) RotateAxis(axis *Point, angle float64) Matrix {
+	return m.then(func(p *Point) { p.RotateAxis(axis, angle) })
+}
+
+// RotateQuat returns this matrix followed by a rotation by the given quaternion.
+func (m Matrix) RotateQuat(q Quaternion) Matrix {
+	return m.then(func(p *Point) { p.RotateQuat(q) })
+}
+
+// Scale returns this matrix followed by a scale on all axes.
+func (m Matrix) Scale(sx, sy, sz float64) Matrix {
+	return m.then(func(p *Point) { p.Scale(sx, sy, sz) })
+}
+
+// UniScale returns this matrix followed by a scale of the same amount on each axis.
+func (m Matrix) UniScale(scale float64) Matrix {
+	return m.then(func(p *Point) { p.UniScale(scale) })
+}
+
+// Shear returns this matrix followed by a shear on all three planes. See Point.Shear.
+func (m Matrix) Shear(xy, xz, yz float64) Matrix {
+	return m.then(func(p *Point) { p.Shear(xy, xz, yz) })
+}
+
+// Transform transforms this point by the given matrix, in place.
+func (p *Point) Transform(m Matrix) {
+	x, y, z := p.X, p.Y, p.Z
+	p.X = m[0]*x + m[1]*y + m[2]*z + m[3]
+	p.Y = m[4]*x + m[5]*y + m[6]*z + m[7]
+	p.Z = m[8]*x + m[9]*y + m[10]*z + m[11]
+}
+
+// Transformed returns a copy of this point, transformed by the given matrix.
+func (p *Point) Transformed(m Matrix) *Point {
+	p1 := p.Clone()
+	p1.Transform(m)
+	return p1
+}
+
+// Transform transforms each point in this pointlist by the given matrix, in place.
+func (p PointList) Transform(m Matrix) {
+	p.batch(func(list PointList) {
+		for _, point := range list {
+			point.Transform(m)
+		}
+	})
+}
+
+// Transformed returns a copy of this pointlist, transformed by the given matrix.
+func (p PointList) Transformed(m Matrix) PointList {
+	p1 := p.Clone()
+	p1.Transform(m)
+	return p1
+}
+
+// Transform transforms this shape by the given matrix, in place.
+func (s *Shape) Transform(m Matrix) {
+	s.transformTarget().Transform(m)
+}
+
+// Transformed returns a copy of this shape, transformed by the given matrix.
+func (s *Shape) Transformed(m Matrix) *Shape {
+	s1 := s.Clone()
+	s1.Transform(m)
+	return s1
+}
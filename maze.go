@@ -0,0 +1,151 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/bit101/bitlib/blmath"
+)
+
+// Maze creates a flat maze on the x/z plane, centered on the origin, made of the segments of its walls.
+// The maze is generated with a recursive backtracker, so every cell can be reached from every other cell
+// by exactly one path. The same seed will always produce the same maze.
+func Maze(w, d float64, cols, rows int, seed int64) *Shape {
+	return mazeShape(cols, rows, seed, false, false, func(u, v float64) (float64, float64, float64) {
+		return u*w - w/2, 0, v*d - d/2
+	})
+}
+
+// MazeCylinder creates a maze wrapped around the outside of a vertical cylinder, made of the segments of its walls.
+// The columns of the maze wrap all the way around the cylinder, so paths can cross the seam.
+func MazeCylinder(height, radius float64, cols, rows int, seed int64) *Shape {
+	return mazeShape(cols, rows, seed, true, false, func(u, v float64) (float64, float64, float64) {
+		a := u * blmath.Tau
+		return math.Cos(a) * radius, v*height - height/2, math.Sin(a) * radius
+	})
+}
+
+// MazeSphere creates a maze covering a sphere, made of the segments of its walls.
+// Columns run around the sphere like lines of longitude and wrap around, and rows run from pole to pole.
+func MazeSphere(radius float64, cols, rows int, seed int64) *Shape {
+	return mazeShape(cols, rows, seed, true, true, func(u, v float64) (float64, float64, float64) {
+		a := u * blmath.Tau
+		lat := v * math.Pi
+		return math.Sin(lat) * math.Cos(a) * radius, math.Cos(lat) * radius, math.Sin(lat) * math.Sin(a) * radius
+	})
+}
+
+// mazeShape generates a maze and maps its walls to 3d with the given function,
+// which takes u and v from 0 to 1 across the columns and rows of the maze.
+// If wrap is true, the last column connects to the first. If poles is true, the top and bottom
+// edges of the maze are each collapsed into a single point. A maze with no columns or rows is an empty shape.
+func mazeShape(cols, rows int, seed int64, wrap, poles bool, mapping func(u, v float64) (float64, float64, float64)) *Shape {
+	shape := NewShape()
+	if cols < 1 || rows < 1 {
+		return shape
+	}
+	east, south := generateMaze(cols, rows, seed, wrap)
+
+	vertices := map[[2]int]int{}
+	vertex := func(i, j int) int {
+		if wrap {
+			i %= cols
+		}
+		if poles && (j == 0 || j == rows) {
+			i = 0
+		}
+		key := [2]int{i, j}
+		if index, ok := vertices[key]; ok {
+			return index
+		}
+		shape.AddXYZ(mapping(float64(i)/float64(cols), float64(j)/float64(rows)))
+		vertices[key] = len(shape.Points) - 1
+		return vertices[key]
+	}
+
+	// vertical walls, between columns.
+	for j := range rows {
+		if !wrap {
+			shape.AddSegmentByIndex(vertex(0, j), vertex(0, j+1))
+		}
+		for i := range cols {
+			if east[j][i] {
+				shape.AddSegmentByIndex(vertex(i+1, j), vertex(i+1, j+1))
+			}
+		}
+	}
+	// horizontal walls, between rows.
+	for i := range cols {
+		if !poles {
+			shape.AddSegmentByIndex(vertex(i, 0), vertex(i+1, 0))
+		}
+		for j := range rows {
+			if south[j][i] && (!poles || j < rows-1) {
+				shape.AddSegmentByIndex(vertex(i, j+1), vertex(i+1, j+1))
+			}
+		}
+	}
+	return shape
+}
+
+// generateMaze creates a maze with a recursive backtracker and returns which walls remain.
+// east[row][col] is the wall on the east side of a cell. south[row][col] is the wall on its south side.
+// Walls on the outer edge are always present, except the east edge when wrap is true.
+func generateMaze(cols, rows int, seed int64, wrap bool) ([][]bool, [][]bool) {
+	rng := rand.New(rand.NewSource(seed))
+	east := make([][]bool, rows)
+	south := make([][]bool, rows)
+	visited := make([][]bool, rows)
+	for j := range rows {
+		east[j] = make([]bool, cols)
+		south[j] = make([]bool, cols)
+		visited[j] = make([]bool, cols)
+		for i := range cols {
+			east[j][i] = true
+			south[j][i] = true
+		}
+	}
+
+	stack := [][2]int{{0, 0}}
+	visited[0][0] = true
+	for len(stack) > 0 {
+		cell := stack[len(stack)-1]
+		i, j := cell[0], cell[1]
+
+		// unvisited neighbors
+		neighbors := [][2]int{}
+		for _, dir := range [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			ni, nj := i+dir[0], j+dir[1]
+			if wrap {
+				ni = (ni + cols) % cols
+			}
+			if ni >= 0 && ni < cols && nj >= 0 && nj < rows && !visited[nj][ni] {
+				neighbors = append(neighbors, dir)
+			}
+		}
+		if len(neighbors) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		dir := neighbors[rng.Intn(len(neighbors))]
+		ni, nj := i+dir[0], j+dir[1]
+		if wrap {
+			ni = (ni + cols) % cols
+		}
+		switch dir {
+		case [2]int{1, 0}:
+			east[j][i] = false
+		case [2]int{-1, 0}:
+			east[nj][ni] = false
+		case [2]int{0, 1}:
+			south[j][i] = false
+		case [2]int{0, -1}:
+			south[nj][ni] = false
+		}
+		visited[nj][ni] = true
+		stack = append(stack, [2]int{ni, nj})
+	}
+	return east, south
+}
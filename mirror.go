@@ -0,0 +1,96 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math"
+	"slices"
+)
+
+// Mirror reflects this point across the plane through planePoint with the given normal, in place.
+// The normal does not need to be normalized.
+func (p *Point) Mirror(planePoint, planeNormal *Point) {
+	n := planeNormal.Normalized()
+	d := (p.X-planePoint.X)*n.X + (p.Y-planePoint.Y)*n.Y + (p.Z-planePoint.Z)*n.Z
+	p.X -= 2 * d * n.X
+	p.Y -= 2 * d * n.Y
+	p.Z -= 2 * d * n.Z
+}
+
+// Mirrored returns a copy of this point, reflected across the plane through planePoint with the given normal.
+func (p *Point) Mirrored(planePoint, planeNormal *Point) *Point {
+	p1 := p.Clone()
+	p1.Mirror(planePoint, planeNormal)
+	return p1
+}
+
+// Mirror reflects each point in this pointlist across the plane through planePoint with the given normal, in place.
+func (p PointList) Mirror(planePoint, planeNormal *Point) {
+	for _, point := range p {
+		point.Mirror(planePoint, planeNormal)
+	}
+}
+
+// Mirror reflects this shape across the plane through planePoint with the given normal, in place.
+// The points of each face are reversed, so faces keep facing outward.
+func (s *Shape) Mirror(planePoint, planeNormal *Point) {
+	s.transformTarget().Mirror(planePoint, planeNormal)
+	for _, face := range s.Faces {
+		slices.Reverse(face.Points)
+	}
+}
+
+// Mirrored returns a copy of this shape, reflected across the plane through planePoint with the given normal.
+func (s *Shape) Mirrored(planePoint, planeNormal *Point) *Shape {
+	s1 := s.Clone()
+	s1.Mirror(planePoint, planeNormal)
+	return s1
+}
+
+// Symmetrize adds a mirrored copy of this shape's points, segments and faces to the shape, reflected across
+// the plane through planePoint with the given normal. Use it to build a symmetrical model from one half.
+// Points lying on the plane are shared by both halves rather than copied, so the seam is welded,
+// and segments and faces lying entirely on the plane are not duplicated.
+func (s *Shape) Symmetrize(planePoint, planeNormal *Point) {
+	s.ApplyTransform()
+	s.Recipe = nil
+	w, h, d := s.GetSize()
+	tolerance := math.Max(w, math.Max(h, d)) * 1e-6
+	n := planeNormal.Normalized()
+	mirrored := map[*Point]*Point{}
+	for _, p := range slices.Clone(s.Points) {
+		dist := (p.X-planePoint.X)*n.X + (p.Y-planePoint.Y)*n.Y + (p.Z-planePoint.Z)*n.Z
+		if math.Abs(dist) <= tolerance {
+			mirrored[p] = p
+			continue
+		}
+		m := p.Mirrored(planePoint, n)
+		s.AddPoint(m)
+		mirrored[p] = m
+	}
+
+	for _, seg := range slices.Clone(s.Segments) {
+		a, okA := mirrored[seg.PointA]
+		b, okB := mirrored[seg.PointB]
+		if !okA || !okB || (a == seg.PointA && b == seg.PointB) {
+			continue
+		}
+		s.AddSegment(&Segment{a, b, seg.Width})
+	}
+
+	for _, face := range slices.Clone(s.Faces) {
+		points := NewPointList()
+		onPlane := true
+		for i := len(face.Points) - 1; i >= 0; i-- {
+			p := face.Points[i]
+			m, ok := mirrored[p]
+			if !ok {
+				m = p.Mirrored(planePoint, n)
+			}
+			onPlane = onPlane && m == p
+			points.Add(m)
+		}
+		if !onPlane {
+			s.AddFace(NewFace(points...))
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/bit101/bitlib/random"
+)
+
+// MutationOp is one kind of random change to a shape, used by Mutate and Evolution.
+// It changes the shape in place. rate is the chance, from 0 to 1, that each point or segment is changed.
+type MutationOp func(s *Shape, rate float64)
+
+// MutateJitter returns a mutation that moves points by a random amount of up to amount on each axis.
+func MutateJitter(amount float64) MutationOp {
+	return func(s *Shape, rate float64) {
+		for _, p := range s.Points {
+			if random.Float() < rate {
+				p.Randomize(amount)
+			}
+		}
+	}
+}
+
+// MutateSplit returns a mutation that splits segments in two at their midpoints, adding detail
+// for other mutations to work on.
+func MutateSplit() MutationOp {
+	return func(s *Shape, rate float64) {
+		for _, seg := range slices.Clone(s.Segments) {
+			if random.Float() < rate {
+				mid := LerpPoint(0.5, seg.PointA, seg.PointB)
+				s.AddPoint(mid)
+				s.AddSegment(&Segment{mid, seg.PointB, seg.Width})
+				seg.PointB = mid
+			}
+		}
+	}
+}
+
+// MutateRemoveSegment returns a mutation that removes segments. Points are left in place.
+func MutateRemoveSegment() MutationOp {
+	return func(s *Shape, rate float64) {
+		s.Segments = slices.DeleteFunc(s.Segments, func(seg *Segment) bool {
+			return random.Float() < rate
+		})
+	}
+}
+
+// MutateAddSegment returns a mutation that connects points to other randomly chosen points in the shape.
+func MutateAddSegment() MutationOp {
+	return func(s *Shape, rate float64) {
+		n := len(s.Points)
+		if n < 2 {
+			return
+		}
+		for i := range n {
+			if random.Float() < rate {
+				// pick any point but this one.
+				j := (i + random.IntRange(1, n)) % n
+				s.AddSegmentByIndex(i, j)
+			}
+		}
+	}
+}
+
+// Mutate returns a randomly changed copy of the shape, made by applying each of the ops in order.
+// rate is the chance, from 0 to 1, that each point or segment is changed by each op.
+// The original shape is unchanged.
+func Mutate(s *Shape, rate float64, ops []MutationOp) *Shape {
+	mutant := s.Clone()
+	mutant.ReleaseOriginal()
+	mutant.Recipe = nil
+	for _, op := range ops {
+		op(mutant, rate)
+	}
+	return mutant
+}
+
+// Evolution evolves a population of shapes towards higher scores from a fitness function.
+// Each generation, every shape is scored, the best few survive unchanged, and the rest of the population
+// is replaced by mutated copies of the survivors.
+type Evolution struct {
+	Population []*Shape
+	Rate       float64
+	Ops        []MutationOp
+	Fitness    func(s *Shape) float64
+	// Survivors is the number of shapes kept each generation. Defaults to a quarter of the population.
+	Survivors  int
+	Generation int
+	scores     []float64
+}
+
+// NewEvolution creates a new evolution with a population of the given size, made of mutated copies of seed.
+// fitness should return higher values for more desirable shapes.
+func NewEvolution(seed *Shape, size int, rate float64, ops []MutationOp, fitness func(s *Shape) float64) *Evolution {
+	e := &Evolution{
+		Rate:      rate,
+		Ops:       ops,
+		Fitness:   fitness,
+		Survivors: max(1, size/4),
+	}
+	for range size {
+		e.Population = append(e.Population, Mutate(seed, rate, ops))
+	}
+	return e
+}
+
+// Step runs a single generation. Afterwards, the population is sorted from the best scoring shape
+// of the generation to the newest mutants.
+func (e *Evolution) Step() {
+	scores := make([]float64, len(e.Population))
+	for i, s := range e.Population {
+		scores[i] = e.Fitness(s)
+	}
+	order := make([]int, len(e.Population))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	survivors := min(max(1, e.Survivors), len(e.Population))
+	next := make([]*Shape, 0, len(e.Population))
+	e.scores = e.scores[:0]
+	for _, i := range order[:survivors] {
+		next = append(next, e.Population[i])
+		e.scores = append(e.scores, scores[i])
+	}
+	for i := survivors; i < len(e.Population); i++ {
+		next = append(next, Mutate(next[i%survivors], e.Rate, e.Ops))
+	}
+	e.Population = next
+	e.Generation++
+}
+
+// Run runs the given number of generations, and returns the best shape and its score.
+func (e *Evolution) Run(generations int) (*Shape, float64) {
+	for range generations {
+		e.Step()
+	}
+	return e.Best()
+}
+
+// Best returns the best shape found so far and its score.
+// Before the first step, this is the first shape in the population, scored now.
+func (e *Evolution) Best() (*Shape, float64) {
+	if len(e.scores) == 0 {
+		return e.Population[0], e.Fitness(e.Population[0])
+	}
+	return e.Population[0], e.scores[0]
+}
@@ -0,0 +1,119 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//////////////////////////////////////////////////////////////
+// Wavefront OBJ files describe polygon meshes.
+//
+// Only vertex (v) and face (f) lines are used. All other lines,
+// such as normals, texture coords, groups and materials, are ignored.
+//
+// v x y z
+// f v1 v2 v3 ...
+//
+// Face vertices may be in any of the forms v, v/vt, v//vn or v/vt/vn.
+// Indexes start at 1. Negative indexes count back from the most
+// recently defined vertex.
+//////////////////////////////////////////////////////////////
+
+// ShapeFromOBJ creates a new shape from a Wavefront .obj file.
+// Each face is added to the shape, and each unique face edge is added as a segment.
+// Any problem in the file is returned as an error. Use ImportOBJ to load as much of a damaged file as possible.
+func ShapeFromOBJ(fileName string) (*Shape, error) {
+	shape, diag, err := ImportOBJ(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if diag.HasWarnings() {
+		return nil, errors.New("unable to parse obj: " + diag.Warnings[0].String())
+	}
+	return shape, nil
+}
+
+// ImportOBJ creates a new shape from a Wavefront .obj file, as with ShapeFromOBJ, skipping any vertices that can't
+// be parsed, and faces that can't be parsed or that use missing or out of range vertices, and reporting them in
+// the returned diagnostics. An error is only returned if the file can't be read.
+func ImportOBJ(fileName string) (*Shape, *Diagnostics, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, nil, errors.New("unable to load obj: " + err.Error())
+	}
+	defer file.Close()
+
+	shape := NewShape()
+	diag := newDiagnostics(fileName)
+	// the index in the shape of each vertex in the file, or -1 for vertices that couldn't be parsed.
+	vertices := []int{}
+	edges := map[[2]int]bool{}
+	lineNum := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNum++
+		fields, columns := fieldsWithColumns(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "v":
+			coords, ok := diag.parseFloats(lineNum, fields[1:], columns[1:], 3)
+			if !ok {
+				vertices = append(vertices, -1)
+				continue
+			}
+			vertices = append(vertices, len(shape.Points))
+			shape.AddXYZ(coords[0], coords[1], coords[2])
+
+		case "f":
+			indices := []int{}
+			for j, field := range fields[1:] {
+				col := columns[j+1]
+				i, err := strconv.Atoi(strings.Split(field, "/")[0])
+				if err != nil {
+					diag.warn(lineNum, col, "bad vertex index %q", field)
+					break
+				}
+				if i < 0 {
+					i += len(vertices)
+				} else {
+					i--
+				}
+				if i < 0 || i >= len(vertices) {
+					diag.warn(lineNum, col, "vertex index %s out of range", field)
+					break
+				}
+				if vertices[i] < 0 {
+					diag.warn(lineNum, col, "vertex %s could not be parsed", field)
+					break
+				}
+				indices = append(indices, vertices[i])
+			}
+			if len(indices) < len(fields)-1 {
+				continue
+			}
+			if len(indices) < 3 {
+				diag.warn(lineNum, 0, "face needs at least three vertices")
+				continue
+			}
+			shape.AddFaceByIndex(indices...)
+			for j, a := range indices {
+				b := indices[(j+1)%len(indices)]
+				key := [2]int{min(a, b), max(a, b)}
+				if !edges[key] {
+					edges[key] = true
+					shape.AddSegmentByIndex(a, b)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errors.New("unable to load obj: " + err.Error())
+	}
+	return shape, diag, nil
+}
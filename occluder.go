@@ -0,0 +1,144 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// occluder is a solid volume that hides whatever is behind it from the camera.
+type occluder interface {
+	// hides returns whether the line of sight from the camera to a point passes through the volume.
+	hides(camera, p *Point) bool
+}
+
+// occluderSphere is a solid sphere that hides what is behind it.
+type occluderSphere struct {
+	center *Point
+	radius float64
+}
+
+func (o occluderSphere) hides(camera, p *Point) bool {
+	// where the line of sight, camera + (p - camera) * s, crosses the sphere, for s from 0 to 1.
+	dx, dy, dz := p.X-camera.X, p.Y-camera.Y, p.Z-camera.Z
+	fx, fy, fz := camera.X-o.center.X, camera.Y-o.center.Y, camera.Z-o.center.Z
+	a := dx*dx + dy*dy + dz*dz
+	b := 2 * (fx*dx + fy*dy + fz*dz)
+	c := fx*fx + fy*fy + fz*fz - o.radius*o.radius
+	disc := b*b - 4*a*c
+	// a camera inside the sphere would see nothing, so the sphere is ignored.
+	if a == 0 || c < 0 || disc <= 0 {
+		return false
+	}
+	root := math.Sqrt(disc)
+	return (-b-root)/(2*a) < 1 && (-b+root)/(2*a) > 0
+}
+
+// occluderBox is a solid box, aligned with the axes, that hides what is behind it.
+type occluderBox struct {
+	min, max *Point
+}
+
+func (o occluderBox) hides(camera, p *Point) bool {
+	// the slab method: the line of sight hits the box if the ranges where it is between each pair of sides overlap.
+	enter, exit := 0.0, 1.0
+	inside := true
+	for _, axis := range [][4]float64{
+		{camera.X, p.X, o.min.X, o.max.X},
+		{camera.Y, p.Y, o.min.Y, o.max.Y},
+		{camera.Z, p.Z, o.min.Z, o.max.Z},
+	} {
+		from, to, lo, hi := axis[0], axis[1], axis[2], axis[3]
+		inside = inside && from > lo && from < hi
+		d := to - from
+		if d == 0 {
+			if from < lo || from > hi {
+				return false
+			}
+			continue
+		}
+		s0, s1 := (lo-from)/d, (hi-from)/d
+		enter = math.Max(enter, math.Min(s0, s1))
+		exit = math.Min(exit, math.Max(s0, s1))
+	}
+	// a camera inside the box would see nothing, so the box is ignored.
+	return !inside && enter < exit
+}
+
+// AddOccluderSphere adds an invisible sphere that hides segments and points behind it. See ClearOccluders.
+func AddOccluderSphere(center *Point, radius float64) {
+	world.Occluders = append(world.Occluders, occluderSphere{center.Clone(), radius})
+}
+
+// AddOccluderBox adds a solid, invisible box to the world, aligned with the axes, of the given width, height and depth
+// around the center. It hides the parts of segments and points behind it, as with AddOccluderSphere.
+func AddOccluderBox(center *Point, w, h, d float64) {
+	world.Occluders = append(world.Occluders, occluderBox{
+		NewPoint(center.X-w/2, center.Y-h/2, center.Z-d/2),
+		NewPoint(center.X+w/2, center.Y+h/2, center.Z+d/2),
+	})
+}
+
+// ClearOccluders removes all occluders from the world.
+func ClearOccluders() {
+	world.Occluders = nil
+}
+
+// occluded returns whether a point is hidden from the camera by any occluder.
+func occluded(p *Point) bool {
+	if len(world.Occluders) == 0 {
+		return false
+	}
+	camera := NewPoint(0, 0, -world.CZ)
+	for _, o := range world.Occluders {
+		if o.hides(camera, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// occluderSamples is the number of pieces a segment is checked in, to find where it passes behind occluders.
+const occluderSamples = 32
+
+// unoccluded returns the parts of the line from a to b that are not hidden by any occluder,
+// as pairs of positions along it, from 0 to 1.
+func unoccluded(a, b *Point) [][2]float64 {
+	if len(world.Occluders) == 0 {
+		return [][2]float64{{0, 1}}
+	}
+	hidden := func(t float64) bool {
+		return occluded(LerpPoint(t, a, b))
+	}
+	// the line is checked at even steps, and each change between hidden and visible is found more exactly
+	// by bisection. occluders smaller than a step may be missed.
+	edge := func(lo, hi float64, hiddenLo bool) float64 {
+		for range 16 {
+			mid := (lo + hi) / 2
+			if hidden(mid) == hiddenLo {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		return (lo + hi) / 2
+	}
+	parts := [][2]float64{}
+	prev := hidden(0)
+	start := 0.0
+	for i := 1; i <= occluderSamples; i++ {
+		t := float64(i) / occluderSamples
+		h := hidden(t)
+		if h == prev {
+			continue
+		}
+		e := edge(float64(i-1)/occluderSamples, t, prev)
+		if h {
+			parts = append(parts, [2]float64{start, e})
+		} else {
+			start = e
+		}
+		prev = h
+	}
+	if !prev {
+		parts = append(parts, [2]float64{start, 1})
+	}
+	return parts
+}
@@ -0,0 +1,131 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// PlanetParams describes the orbit and size of a planet for Orrery, or of a moon around its planet.
+// Orbits are ellipses on the xz plane, with the body they go around at one focus, tilted around the x-axis by Inclination.
+type PlanetParams struct {
+	// Distance is the semi-major axis of the orbit: half its longest diameter.
+	Distance float64
+	// Eccentricity is how stretched the orbit is, from 0 for a circle up to, but not including, 1.
+	Eccentricity float64
+	// Period is the time taken for one orbit. Bodies with a period of 0 don't move.
+	Period float64
+	// Phase is where the body is on its orbit at time 0, as an angle in radians, measured as mean anomaly.
+	Phase float64
+	// Inclination is the tilt of the orbit around the x-axis, in radians.
+	Inclination float64
+	// Radius is the radius of the body's marker.
+	Radius float64
+	// Moons orbit this body.
+	Moons []PlanetParams
+}
+
+// OrreryBody is a planet or moon in an orrery, with its current position.
+type OrreryBody struct {
+	Params   PlanetParams
+	Position *Point
+	Moons    []*OrreryBody
+}
+
+// OrreryModel is a model of planets orbiting a sun at the origin, and of moons orbiting the planets.
+// Call Update each frame to move the bodies along their orbits, then Shape to draw them.
+type OrreryModel struct {
+	Bodies []*OrreryBody
+	// ShowOrbits draws each orbit as an ellipse. Defaults to true.
+	ShowOrbits bool
+	// OrbitRes is the number of segments in each orbit's ellipse. Defaults to 64.
+	OrbitRes int
+	// MarkerRes is the number of segments around each body's marker sphere. Defaults to 8.
+	MarkerRes int
+}
+
+// Orrery creates a model of the given planets orbiting a sun at the origin, with the bodies at their positions for time 0.
+func Orrery(planets []PlanetParams) *OrreryModel {
+	o := &OrreryModel{
+		Bodies:     newOrreryBodies(planets),
+		ShowOrbits: true,
+		OrbitRes:   64,
+		MarkerRes:  8,
+	}
+	o.Update(0)
+	return o
+}
+
+// newOrreryBodies creates bodies, and their moons, for the given params.
+func newOrreryBodies(params []PlanetParams) []*OrreryBody {
+	bodies := []*OrreryBody{}
+	for _, p := range params {
+		bodies = append(bodies, &OrreryBody{p, NewPoint(0, 0, 0), newOrreryBodies(p.Moons)})
+	}
+	return bodies
+}
+
+// Update moves every body to its position at time t. Each body sweeps around its orbit faster when it is near
+// the body it orbits and slower when it is far away, as real orbits do.
+func (o *OrreryModel) Update(t float64) {
+	updateOrreryBodies(o.Bodies, NewPoint(0, 0, 0), t)
+}
+
+// updateOrreryBodies moves the bodies, and their moons, to their positions at time t, around the given center.
+func updateOrreryBodies(bodies []*OrreryBody, center *Point, t float64) {
+	for _, b := range bodies {
+		mean := b.Params.Phase
+		if b.Params.Period != 0 {
+			mean += 2 * math.Pi * t / b.Params.Period
+		}
+		b.Position = b.Params.orbitPoint(eccentricAnomaly(mean, b.Params.Eccentricity))
+		b.Position.Translate(center.X, center.Y, center.Z)
+		updateOrreryBodies(b.Moons, b.Position, t)
+	}
+}
+
+// eccentricAnomaly solves Kepler's equation, M = E - e sin E, for the eccentric anomaly E,
+// given the mean anomaly M and eccentricity e.
+func eccentricAnomaly(mean, e float64) float64 {
+	angle := mean
+	for range 8 {
+		angle -= (angle - e*math.Sin(angle) - mean) / (1 - e*math.Cos(angle))
+	}
+	return angle
+}
+
+// orbitPoint returns the point on the orbit at the given eccentric anomaly, relative to the body being orbited.
+func (p PlanetParams) orbitPoint(anomaly float64) *Point {
+	minor := p.Distance * math.Sqrt(1-p.Eccentricity*p.Eccentricity)
+	point := NewPoint(p.Distance*(math.Cos(anomaly)-p.Eccentricity), 0, minor*math.Sin(anomaly))
+	point.RotateX(p.Inclination)
+	return point
+}
+
+// Shape returns a shape with a sphere marking each body at its current position, and an ellipse for each orbit
+// if ShowOrbits is true. Moons' orbits are centered on their planets' current positions.
+func (o *OrreryModel) Shape() *Shape {
+	shape := NewShape()
+	o.addBodies(shape, o.Bodies, NewPoint(0, 0, 0))
+	return shape
+}
+
+// addBodies adds the markers and orbits of the bodies, and their moons, around the given center, to the shape.
+func (o *OrreryModel) addBodies(shape *Shape, bodies []*OrreryBody, center *Point) {
+	res := max(3, o.MarkerRes)
+	for _, b := range bodies {
+		if o.ShowOrbits {
+			orbit := NewShape()
+			count := max(3, o.OrbitRes)
+			for i := range count {
+				orbit.AddPoint(b.Params.orbitPoint(2 * math.Pi * float64(i) / float64(count)))
+			}
+			for i := range count {
+				orbit.AddSegmentByIndex(i, (i+1)%count)
+			}
+			orbit.Translate(center.X, center.Y, center.Z)
+			shape.AddShape(orbit)
+		}
+		marker := Sphere(b.Params.Radius, max(2, res/2), res, true, true)
+		marker.Translate(b.Position.X, b.Position.Y, b.Position.Z)
+		shape.AddShape(marker)
+		o.addBodies(shape, b.Moons, b.Position)
+	}
+}
@@ -0,0 +1,80 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// Corner is a corner of the screen, for PinToCorner.
+type Corner int
+
+const (
+	// TopLeft is the top left corner of the screen.
+	TopLeft Corner = iota
+	// TopRight is the top right corner of the screen.
+	TopRight
+	// BottomLeft is the bottom left corner of the screen.
+	BottomLeft
+	// BottomRight is the bottom right corner of the screen.
+	BottomRight
+)
+
+// ToScreen moves the shape onto the focal plane, where one unit is one pixel, so that its origin is drawn
+// at pixel x, y on the screen. A flat shape on the xy plane, such as text, is then drawn flat and facing the viewer
+// at its actual size, wherever the world's center is, making it an overlay on top of the 3d scene.
+// Fog and other depth effects still apply at the depth of the focal plane.
+func (s *Shape) ToScreen(x, y float64) {
+	s.Translate(x-world.CX, y-world.CY, world.FL-world.CZ)
+}
+
+// PinToCorner moves the shape onto the screen as with ToScreen, so that its bounds sit the given margin in
+// from a corner of a screen of the given width and height, such as for a label, timecode or stats display.
+func (s *Shape) PinToCorner(corner Corner, width, height, margin float64) {
+	b := s.GetBounds()
+	x, y := margin-b.MinX, margin-b.MinY
+	if corner == TopRight || corner == BottomRight {
+		x = width - margin - b.MaxX
+	}
+	if corner == BottomLeft || corner == BottomRight {
+		y = height - margin - b.MaxY
+	}
+	s.ToScreen(x, y)
+}
+
+// SafeAreaGuides creates broadcast style safe area guides for a screen centered on the world's center:
+// a rectangle for the action safe area, inside which anything important should be kept, a smaller one for
+// the title safe area, inside which text should be kept, and a cross at the center. Each area is given as
+// a fraction of the screen's width and height, such as 0.93 and 0.9, or 0.9 and 0.8 for older standards.
+// An area of 0 leaves its rectangle out. The guides lie on the focal plane, as with ToScreen, so stroke them
+// last, at a width of 1, over the rest of the frame. The screen size is the one given to InitWorldForCanvas,
+// or twice the world's center otherwise.
+func SafeAreaGuides(percentAction, percentTitle float64) *Shape {
+	width, height := canvasSize()
+	shape := NewShape()
+	rect := func(percent float64) {
+		if percent <= 0 {
+			return
+		}
+		w, h := width*percent/2, height*percent/2
+		start := len(shape.Points)
+		shape.AddXYZ(-w, -h, 0)
+		shape.AddXYZ(w, -h, 0)
+		shape.AddXYZ(w, h, 0)
+		shape.AddXYZ(-w, h, 0)
+		for i := range 4 {
+			shape.AddSegmentByIndex(start+i, start+(i+1)%4)
+		}
+	}
+	rect(percentAction)
+	rect(percentTitle)
+
+	arm := math.Min(width, height) * 0.025
+	start := len(shape.Points)
+	shape.AddXYZ(-arm, 0, 0)
+	shape.AddXYZ(arm, 0, 0)
+	shape.AddXYZ(0, -arm, 0)
+	shape.AddXYZ(0, arm, 0)
+	shape.AddSegmentByIndex(start, start+1)
+	shape.AddSegmentByIndex(start+2, start+3)
+
+	shape.ToScreen(world.CX, world.CY)
+	return shape
+}
@@ -0,0 +1,48 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math"
+
+	"github.com/bit101/bitlib/blcolor"
+	"github.com/bit101/bitlib/blmath"
+)
+
+// Palette is a list of colors that values from 0 to 1 can be mapped through.
+type Palette []blcolor.Color
+
+// PaletteHeat is a palette running from black through red and yellow to white.
+var PaletteHeat = NewPalette(
+	blcolor.RGB(0, 0, 0),
+	blcolor.RGB(1, 0, 0),
+	blcolor.RGB(1, 1, 0),
+	blcolor.RGB(1, 1, 1),
+)
+
+// NewPalette creates a new palette from the given colors.
+func NewPalette(colors ...blcolor.Color) Palette {
+	return Palette(colors)
+}
+
+// Color returns the color at position t in the palette, where 0 is the first color and 1 is the last.
+// Values in between are interpolated between the neighboring colors. t is clamped to 0 to 1.
+// An empty palette gives the world color.
+func (p Palette) Color(t float64) blcolor.Color {
+	if len(p) == 0 {
+		return blcolor.RGB(world.R, world.G, world.B)
+	}
+	if len(p) == 1 {
+		return p[0]
+	}
+	t = blmath.Clamp(t, 0, 1) * float64(len(p)-1)
+	index := math.Min(math.Floor(t), float64(len(p)-2))
+	a := p[int(index)]
+	b := p[int(index)+1]
+	t -= index
+	return blcolor.RGBA(
+		blmath.Lerp(t, a.R, b.R),
+		blmath.Lerp(t, a.G, b.G),
+		blmath.Lerp(t, a.B, b.B),
+		blmath.Lerp(t, a.A, b.A),
+	)
+}
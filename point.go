@@ -2,6 +2,7 @@
 package wire
 
 import (
+	"maps"
 	"math"
 
 	"github.com/bit101/bitlib/blmath"
@@ -12,11 +13,12 @@ import (
 type Point struct {
 	X, Y, Z         float64
 	Px, Py, Scaling float64
+	Attrs           map[string]float64
 }
 
 // NewPoint creates a new 3d point.
 func NewPoint(x, y, z float64) *Point {
-	return &Point{x, y, z, 0, 0, 0}
+	return &Point{x, y, z, 0, 0, 0, nil}
 }
 
 // LerpPoint creates a new 3d point interpolated from the two given points.
@@ -182,7 +184,21 @@ func RandomPointInTorus(radius1, radius2, arc float64) *Point {
 
 // Clone returns a copy of this point.
 func (p *Point) Clone() *Point {
-	return &Point{p.X, p.Y, p.Z, p.Px, p.Py, p.Scaling}
+	return &Point{p.X, p.Y, p.Z, p.Px, p.Py, p.Scaling, maps.Clone(p.Attrs)}
+}
+
+// SetAttr sets a named scalar attribute on this point, such as intensity or temperature.
+func (p *Point) SetAttr(name string, value float64) {
+	if p.Attrs == nil {
+		p.Attrs = map[string]float64{}
+	}
+	p.Attrs[name] = value
+}
+
+// GetAttr returns the value of a named attribute on this point, and whether it was set.
+func (p *Point) GetAttr(name string) (float64, bool) {
+	value, ok := p.Attrs[name]
+	return value, ok
 }
 
 // Lerp interpolates this point to another point, in place.
@@ -198,13 +214,19 @@ func (p *Point) Magnitude() float64 {
 }
 
 // Project projects this 3d point to a 2d point, by setting the Px, Py and Scaling properties of this point.
+// Points at or behind the camera are projected as if they were a tiny distance in front of it,
+// so the result is always finite. Such points are never visible.
 func (p *Point) Project() {
-	scale := world.FL / (world.CZ + p.Z)
+	scale := world.FL / math.Max(world.CZ+p.Z, minDepth)
 	p.Px = world.CX + p.X*scale
 	p.Py = world.CY + p.Y*scale
 	p.Scaling = scale
 }
 
+// minDepth is the smallest depth used for projection, guarding against division by zero
+// and inverted projection for points at or behind the camera.
+const minDepth = 1e-6
+
 // Distance returns the distance from this point to another point.
 func (p *Point) Distance(other *Point) float64 {
 	dx := other.X - p.X
@@ -287,6 +309,22 @@ func (p *Point) Rotate(rx, ry, rz float64) {
 	p.RotateZ(rz)
 }
 
+// RotateAxis rotates this point around an axis through the origin in the direction of the given vector, in place.
+// The axis does not need to be normalized. Positive angles follow the right hand rule around the axis,
+// the same direction as RotateY and RotateZ turn around theirs. Note that RotateX turns the other way,
+// so RotateAxis(NewPoint(1, 0, 0), angle) is the same as RotateX(-angle).
+// https://en.wikipedia.org/wiki/Rodrigues%27_rotation_formula
+func (p *Point) RotateAxis(axis *Point, angle float64) {
+	k := axis.Normalized()
+	c := math.Cos(angle)
+	s := math.Sin(angle)
+	dot := (k.X*p.X + k.Y*p.Y + k.Z*p.Z) * (1 - c)
+	x := p.X*c + (k.Y*p.Z-k.Z*p.Y)*s + k.X*dot
+	y := p.Y*c + (k.Z*p.X-k.X*p.Z)*s + k.Y*dot
+	z := p.Z*c + (k.X*p.Y-k.Y*p.X)*s + k.Z*dot
+	p.X, p.Y, p.Z = x, y, z
+}
+
 // ScaleX scales this point on the x-axis, in place.
 func (p *Point) ScaleX(scale float64) {
 	p.X *= scale
@@ -316,6 +354,28 @@ func (p *Point) UniScale(scale float64) {
 	p.Z *= scale
 }
 
+// ShearXY shears this point on the xy plane, in place, moving it on the x-axis by amount times its y value.
+func (p *Point) ShearXY(amount float64) {
+	p.X += p.Y * amount
+}
+
+// ShearXZ shears this point on the xz plane, in place, moving it on the x-axis by amount times its z value.
+func (p *Point) ShearXZ(amount float64) {
+	p.X += p.Z * amount
+}
+
+// ShearYZ shears this point on the yz plane, in place, moving it on the y-axis by amount times its z value.
+func (p *Point) ShearYZ(amount float64) {
+	p.Y += p.Z * amount
+}
+
+// Shear shears this point on all three planes, in place. See ShearXY, ShearXZ and ShearYZ.
+// The x-axis shears are applied using the original y and z values.
+func (p *Point) Shear(xy, xz, yz float64) {
+	p.X += p.Y*xy + p.Z*xz
+	p.Y += p.Z * yz
+}
+
 // RandomizeX randomizes this point on the x-axis, in place.
 func (p *Point) RandomizeX(amount float64) {
 	p.X += random.FloatRange(-amount, amount)
@@ -338,6 +398,44 @@ func (p *Point) Randomize(amount float64) {
 	p.Z += random.FloatRange(-amount, amount)
 }
 
+// RandomDistribution is the shape of the random distribution used by RandomizeAxes.
+type RandomDistribution int
+
+const (
+	// RandomUniform moves points by any amount from -amount to amount with equal likelihood,
+	// like Randomize. This gives an even, static-like jitter.
+	RandomUniform RandomDistribution = iota
+	// RandomGaussian moves points by a normally distributed amount, with amount as the standard deviation.
+	// Most points move a little and a few move a lot, which looks more organic.
+	RandomGaussian
+	// RandomExponential moves points by an exponentially distributed amount in either direction,
+	// with amount as the average. Most points barely move, with occasional large spikes.
+	RandomExponential
+)
+
+// randomOffset returns a random offset for the given amount and distribution.
+func randomOffset(amount float64, dist RandomDistribution) float64 {
+	switch dist {
+	case RandomGaussian:
+		return random.Norm(0, amount)
+	case RandomExponential:
+		offset := -math.Log(1-random.Float()) * amount
+		if random.Boolean() {
+			return -offset
+		}
+		return offset
+	default:
+		return random.FloatRange(-amount, amount)
+	}
+}
+
+// RandomizeAxes randomizes this point by a separate amount on each axis, using the given distribution, in place.
+func (p *Point) RandomizeAxes(ax, ay, az float64, dist RandomDistribution) {
+	p.X += randomOffset(ax, dist)
+	p.Y += randomOffset(ay, dist)
+	p.Z += randomOffset(az, dist)
+}
+
 // Normalize normalizes each component ofthis point, in place.
 func (p *Point) Normalize() {
 	mag := p.Magnitude()
@@ -406,6 +504,13 @@ func (p *Point) Rotated(rx, ry, rz float64) *Point {
 	return p1
 }
 
+// RotatedAxis returns a copy of this point, rotated around an arbitrary axis through the origin.
+func (p *Point) RotatedAxis(axis *Point, angle float64) *Point {
+	p1 := p.Clone()
+	p1.RotateAxis(axis, angle)
+	return p1
+}
+
 // ScaledX returns a copy of this point, scaled on the x-axis.
 func (p *Point) ScaledX(scale float64) *Point {
 	p1 := p.Clone()
@@ -441,6 +546,34 @@ func (p *Point) UniScaled(scale float64) *Point {
 	return p1
 }
 
+// ShearedXY returns a copy of this point, sheared on the xy plane.
+func (p *Point) ShearedXY(amount float64) *Point {
+	p1 := p.Clone()
+	p1.ShearXY(amount)
+	return p1
+}
+
+// ShearedXZ returns a copy of this point, sheared on the xz plane.
+func (p *Point) ShearedXZ(amount float64) *Point {
+	p1 := p.Clone()
+	p1.ShearXZ(amount)
+	return p1
+}
+
+// ShearedYZ returns a copy of this point, sheared on the yz plane.
+func (p *Point) ShearedYZ(amount float64) *Point {
+	p1 := p.Clone()
+	p1.ShearYZ(amount)
+	return p1
+}
+
+// Sheared returns a copy of this point, sheared on all three planes.
+func (p *Point) Sheared(xy, xz, yz float64) *Point {
+	p1 := p.Clone()
+	p1.Shear(xy, xz, yz)
+	return p1
+}
+
 // RandomizedX returns a copy of this point, randomized on the x-axis.
 func (p *Point) RandomizedX(amount float64) *Point {
 	p1 := p.Clone()
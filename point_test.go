@@ -0,0 +1,42 @@
+package wire
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProjectNearCamera(t *testing.T) {
+	saved := world
+	defer func() { world = saved }()
+	SetPerspective(300)
+	SetCenter(400, 300, 500)
+
+	tests := []struct {
+		name    string
+		z       float64
+		visible bool
+	}{
+		{"in front", 0, true},
+		{"at the camera", -500, false},
+		{"just in front of the camera", -500 + 1e-9, false},
+		{"behind the camera", -600, false},
+		{"far behind the camera", -1e12, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPoint(10, -20, tt.z)
+			p.Project()
+			for _, v := range []float64{p.Px, p.Py, p.Scaling} {
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					t.Fatalf("projected to %v, %v, scaling %v", p.Px, p.Py, p.Scaling)
+				}
+			}
+			if p.Scaling <= 0 {
+				t.Errorf("scaling %v, want positive", p.Scaling)
+			}
+			if got := p.Visible(); got != tt.visible {
+				t.Errorf("Visible() = %v, want %v", got, tt.visible)
+			}
+		})
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"math"
 	"slices"
 
+	"github.com/bit101/bitlib/blcolor"
+	"github.com/bit101/bitlib/blmath"
 	"github.com/bit101/bitlib/noise"
+	"github.com/bit101/bitlib/random"
 )
 
 // PointList represents a list of 3d points.
@@ -17,15 +20,37 @@ func NewPointList() PointList {
 	return PointList{}
 }
 
-// Clone returns a deep copy of this pointlist.
+// Clone returns a deep copy of this pointlist. The copied points are stored contiguously, as with Compact.
 func (p PointList) Clone() PointList {
-	list := NewPointList()
-	for _, point := range p {
-		list.Add(point.Clone())
+	list := make(PointList, len(p))
+	slab := make([]Point, len(p))
+	for i, point := range p {
+		slab[i] = *point.Clone()
+		list[i] = &slab[i]
 	}
 	return list
 }
 
+// Compact moves all the points in this list into a single contiguous block of memory, in place.
+// This improves cache locality and reduces garbage collection work for very large lists.
+// The list will then refer to new points, so any other references to the old points
+// (such as segments) will no longer be connected to this list. See Shape.Compact.
+func (p PointList) Compact() {
+	p.compact()
+}
+
+// compact moves the points into a contiguous block and returns a map from old points to new ones.
+func (p PointList) compact() map[*Point]*Point {
+	slab := make([]Point, len(p))
+	remap := make(map[*Point]*Point, len(p))
+	for i, point := range p {
+		slab[i] = *point
+		remap[point] = &slab[i]
+		p[i] = &slab[i]
+	}
+	return remap
+}
+
 // Lerp interpolates thispoint list towards another, in place.
 // Thus, it should maintain segment relationships.
 func (p PointList) Lerp(t float64, other PointList) {
@@ -95,28 +120,131 @@ func (p *PointList) AddRandomPointInTorus(radius1, radius2 float64) {
 
 // Project projects this 3d point list to a 2d point list.
 // This returns a list of 2d points as well as a list of scale values for each point.
+// If boil is on, each point is jittered by its index in the list.
 func (p PointList) Project() {
-	for _, point := range p {
+	p.project(0)
+}
+
+// project projects the points, jittering each one by the given id and its index if boil is on.
+func (p PointList) project(id uint64) {
+	for i, point := range p {
 		point.Project()
+		if world.BoilAmplitude != 0 {
+			dx, dy := boilOffset(id, i)
+			point.Px += dx
+			point.Py += dy
+		}
 	}
 }
 
 // RenderPoints projects and draws a circle for each point in the list.
+// If density alpha is active, points in crowded regions will be drawn with reduced alpha.
 func (p PointList) RenderPoints(radius float64) {
 	p.Project()
+	p.renderPoints(radius, nil, nil)
+}
+
+// renderPoints draws a circle for each point in the list, which must already be projected.
+// If colors is not nil, each point is drawn in the corresponding color.
+// If sizes is not nil, each point's radius is multiplied by the corresponding size.
+func (p PointList) renderPoints(radius float64, colors []blcolor.Color, sizes []float64) {
+	density := p.densityAlpha()
 	for i, point := range p {
-		if point.Visible() {
+		var color *blcolor.Color
+		if colors != nil {
+			color = &colors[i]
+		}
+		r := radius
+		if sizes != nil {
+			r *= sizes[i]
+		}
+		renderPoint(point, i, r, color, density[i])
+	}
+}
+
+// renderPoint draws a circle for a projected point, in the given color if it is not nil, with its alpha
+// multiplied by alpha. If point labels are on, it is labeled with the given index.
+func renderPoint(point *Point, index int, radius float64, color *blcolor.Color, alpha float64) {
+	if !point.Visible() || occluded(point) {
+		renderStats.Culled++
+		return
+	}
+	renderStats.Points++
+	world.Context.Save()
+	shade(color, point.Y, point.Z, alpha)
+	world.Context.FillCircle(snapCoord(point.Px, 1), snapCoord(point.Py, 1), projectedSize(radius, point.Scaling, point.Z))
+	if world.LabelPoints {
+		world.Context.FillTextAny(index, point.Px+5, point.Py-5)
+	}
+	world.Context.Restore()
+}
+
+// RenderSoftPoints projects and draws a soft, radial gradient splat for each point in the list.
+// Each splat is fully opaque at its center and fades to transparent at the given radius.
+// falloff shapes the fade: 1 is linear, higher values give a tighter, brighter core
+// and lower values a broader glow.
+// The gradient is built from a stack of translucent circles, so it works with any Context.
+func (p PointList) RenderSoftPoints(radius, falloff float64) {
+	p.Project()
+	p.renderSoftPoints(radius, falloff)
+}
+
+// renderSoftPoints draws a soft splat for each point in the list, which must already be projected.
+func (p PointList) renderSoftPoints(radius, falloff float64) {
+	density := p.densityAlpha()
+	steps := 12
+	color := blcolor.RGB(world.R, world.G, world.B)
+	for i, point := range p {
+		if point.Visible() && !occluded(point) {
 			world.Context.Save()
-			ApplyFogAndWaterLevel(point.Y, point.Z)
-			world.Context.FillCircle(point.Px, point.Py, radius*point.Scaling)
-			if world.LabelPoints {
-				world.Context.FillTextAny(i, point.Px+5, point.Py-5)
+			prev := 0.0
+			for j := range steps {
+				// target opacity at this ring, and the layer alpha needed to reach it over the rings beneath.
+				// the innermost ring reaches full opacity.
+				t := math.Pow(float64(j+1)/float64(steps), falloff)
+				alpha := 1 - (1-t)/(1-prev)
+				prev = t
+				r := radius * (1 - float64(j)/float64(steps))
+				// always set, as each ring's alpha differs from the last.
+				shade(&color, point.Y, point.Z, alpha*density[i])
+				world.Context.FillCircle(snapCoord(point.Px, 1), snapCoord(point.Py, 1), projectedSize(r, point.Scaling, point.Z))
 			}
 			world.Context.Restore()
 		}
 	}
 }
 
+// densityAlpha returns an alpha multiplier for each point in the list, based on the world's density alpha settings.
+// If density alpha is not active, or its radius is 0 or less, all values will be 1.
+func (p PointList) densityAlpha() []float64 {
+	alphas := make([]float64, len(p))
+	if !world.DensityActive || world.DensityRadius <= 0 {
+		for i := range alphas {
+			alphas[i] = 1
+		}
+		return alphas
+	}
+	grid := NewSpatialGrid(p, world.DensityRadius)
+	for i, point := range p {
+		count := float64(grid.CountNeighbors(point, world.DensityRadius))
+		alphas[i] = math.Min(1, world.DensityCount/math.Max(count, 1))
+	}
+	return alphas
+}
+
+// AttrRange returns the minimum and maximum values of the named attribute across all points in the list
+// that have it set.
+func (p PointList) AttrRange(name string) (float64, float64) {
+	min, max := math.MaxFloat64, -math.MaxFloat64
+	for _, point := range p {
+		if value, ok := point.GetAttr(name); ok {
+			min = math.Min(min, value)
+			max = math.Max(max, value)
+		}
+	}
+	return min, max
+}
+
 // Get returns the point at the given index. Negative indexes go in reverse from end.
 func (p PointList) Get(index int) *Point {
 	if index < 0 {
@@ -235,6 +363,139 @@ func (p PointList) TwistZ(angle float64) {
 	}
 }
 
+// TwistAxis twists the points around an axis through the origin, rotating each by amt * falloff(t),
+// where t runs from 0 to 1 along the points' extent on the axis. A nil falloff twists linearly.
+func (p PointList) TwistAxis(axis *Point, amt float64, falloff func(t float64) float64) {
+	k := axis.Normalized()
+	min, max := math.MaxFloat64, -math.MaxFloat64
+	for _, point := range p {
+		d := point.X*k.X + point.Y*k.Y + point.Z*k.Z
+		min = math.Min(min, d)
+		max = math.Max(max, d)
+	}
+	if max == min {
+		return
+	}
+	for _, point := range p {
+		t := ((point.X*k.X + point.Y*k.Y + point.Z*k.Z) - min) / (max - min)
+		if falloff != nil {
+			t = falloff(t)
+		}
+		point.RotateAxis(k, amt*t)
+	}
+}
+
+// Taper scales the cross-sections of the points progressively along the given axis.
+// Cross-sections keep their size at the low end of the points' extent along the axis,
+// and are scaled towards the axis by 1 - amount at the high end.
+// An amount of 1 tapers to a point, and a negative amount flares outward.
+func (p PointList) Taper(axis Axis, amount float64) {
+	p.TaperWithCurve(axis, amount, nil)
+}
+
+// TaperWithCurve is like Taper, but the taper follows the given curve along the axis
+// rather than changing linearly. The curve has the signature of the bitlib easing functions,
+// such as easing.QuadraticEaseIn, and is called with t from 0 to 1 and a start and end of 0 and 1.
+// A nil curve is linear.
+func (p PointList) TaperWithCurve(axis Axis, amount float64, curve func(t, start, end float64) float64) {
+	min, max := p.axisRange(axis)
+	if max == min {
+		return
+	}
+	for _, point := range p {
+		along, u, v := point.axisCoords(axis)
+		t := (*along - min) / (max - min)
+		if curve != nil {
+			t = curve(t, 0, 1)
+		}
+		scale := 1 - amount*t
+		*u *= scale
+		*v *= scale
+	}
+}
+
+// Spherify moves each point towards its position on the bounding sphere of the list,
+// from 0 (unchanged) to 1 (fully on the sphere). The sphere is centered on the origin,
+// with a radius of the distance to the farthest point. Points at the origin are not moved.
+func (p PointList) Spherify(t float64) {
+	radius := 0.0
+	for _, point := range p {
+		radius = math.Max(radius, point.Magnitude())
+	}
+	for _, point := range p {
+		mag := point.Magnitude()
+		if mag == 0 {
+			continue
+		}
+		point.Lerp(t, NewPoint(point.X/mag*radius, point.Y/mag*radius, point.Z/mag*radius))
+	}
+}
+
+// ProjectToSphere moves each point to the nearest point on a sphere of the given radius, centered on the origin.
+// Points at the origin are not moved.
+func (p PointList) ProjectToSphere(radius float64) {
+	for _, point := range p {
+		mag := point.Magnitude()
+		if mag == 0 {
+			continue
+		}
+		point.X = point.X / mag * radius
+		point.Y = point.Y / mag * radius
+		point.Z = point.Z / mag * radius
+	}
+}
+
+// ProjectToCylinder moves each point to the nearest point on an infinite cylinder of the given radius,
+// standing along the y-axis, like the Cylinder shape. y values are unchanged.
+// Points on the y-axis are not moved.
+func (p PointList) ProjectToCylinder(radius float64) {
+	for _, point := range p {
+		mag := math.Hypot(point.X, point.Z)
+		if mag == 0 {
+			continue
+		}
+		point.X = point.X / mag * radius
+		point.Z = point.Z / mag * radius
+	}
+}
+
+// ProjectToPlane moves each point to the nearest point on the plane through planePoint with the given normal,
+// flattening the list onto the plane. The normal does not need to be normalized.
+func (p PointList) ProjectToPlane(planePoint, planeNormal *Point) {
+	n := planeNormal.Normalized()
+	for _, point := range p {
+		d := (point.X-planePoint.X)*n.X + (point.Y-planePoint.Y)*n.Y + (point.Z-planePoint.Z)*n.Z
+		point.X -= d * n.X
+		point.Y -= d * n.Y
+		point.Z -= d * n.Z
+	}
+}
+
+// Wave displaces the points with a sine wave traveling along the given axis.
+// Points are displaced on the y-axis, or on the z-axis for a wave traveling along the y-axis.
+// Phase is in radians, and increasing it over time moves the wave towards the positive end of the axis.
+func (p PointList) Wave(axis Axis, amplitude, wavelength, phase float64) {
+	for _, point := range p {
+		along, _, _ := point.axisCoords(axis)
+		offset := math.Sin(*along/wavelength*blmath.Tau-phase) * amplitude
+		if axis == AxisY {
+			point.Z += offset
+		} else {
+			point.Y += offset
+		}
+	}
+}
+
+// Ripple displaces the points on the y-axis with circular waves spreading out across the xz plane from center.
+// The distance to center is measured on the xz plane, so the y value of center is ignored.
+// Phase is in radians, and increasing it over time moves the ripples outward.
+func (p PointList) Ripple(center *Point, amplitude, wavelength, phase float64) {
+	for _, point := range p {
+		dist := math.Hypot(point.X-center.X, point.Z-center.Z)
+		point.Y += math.Sin(dist/wavelength*blmath.Tau-phase) * amplitude
+	}
+}
+
 // WrapCylinderWithArc wraps the x-axis of a point list around an imaginary cylinder laying
 // along the z-axis. The point list will retain its relative width, measured along the curve.
 // The radius of the cylinder will be dynamically computed.
@@ -285,6 +546,94 @@ func (p PointList) WrapCylinderWithRadiusAndArc(radius, arc float64) {
 	p.TranslateY(radius)
 }
 
+// WrapSphere wraps a point list lying on the xz plane around an imaginary sphere of the given radius,
+// like a map wrapped onto a globe. The x-axis wraps around the sphere's longitude and the z-axis
+// around its latitude. The point list will be stretched or compressed to cover arcX and arcY.
+// y values become distances above the surface (negative y is outward, as with the cylinder wraps).
+// The sphere's center is at (0, radius, 0), so the point at the origin stays put.
+// t interpolates each point from its flat position (0) to its wrapped position (1), for animation.
+func (p PointList) WrapSphere(radius, arcX, arcY, t float64) {
+	w, _, d := p.GetSize()
+	for _, point := range p {
+		lon := point.X / w * arcX
+		lat := point.Z / d * arcY
+		r := radius - point.Y
+		wrapped := NewPoint(
+			math.Sin(lon)*math.Cos(lat)*r,
+			radius-math.Cos(lon)*math.Cos(lat)*r,
+			math.Sin(lat)*r,
+		)
+		point.Lerp(t, wrapped)
+	}
+}
+
+// WrapTorus wraps a point list lying on the xy plane around an imaginary torus lying on the xz plane,
+// centered on the origin. The x-axis wraps around the ring of radius r1, stretched or compressed to cover arc,
+// and the y-axis wraps all the way around the tube of radius r2.
+// z values become distances above the surface of the tube (negative z is outward).
+// The point at the origin lands on the front of the torus, the part nearest the camera.
+// t interpolates each point from its flat position (0) to its wrapped position (1), for animation.
+func (p PointList) WrapTorus(r1, r2, arc, t float64) {
+	w, h, _ := p.GetSize()
+	for _, point := range p {
+		ring := point.X / w * arc
+		tube := point.Y / h * blmath.Tau
+		r := r1 + (r2-point.Z)*math.Cos(tube)
+		wrapped := NewPoint(
+			math.Sin(ring)*r,
+			math.Sin(tube)*(r2-point.Z),
+			-math.Cos(ring)*r,
+		)
+		point.Lerp(t, wrapped)
+	}
+}
+
+// Spiralize coils a point list laid out along the x-axis into an Archimedean spiral on the xy plane,
+// centered on (0, radius, 0) and moving outward by pitch each turn. t animates from flat (0) to coiled (1).
+func (p PointList) Spiralize(pitch, radius, t float64) {
+	minX, maxX := p.axisRange(AxisX)
+	b := pitch / blmath.Tau
+	if len(p) == 0 || (radius <= 0 && b == 0) {
+		return
+	}
+	// the angle at each distance along the spiral is found by stepping along it from x = 0 in each direction.
+	// the radius stops shrinking at the center, so inward coils end there.
+	const steps = 1000
+	h := math.Max(math.Abs(minX), math.Abs(maxX)) / steps
+	rate := func(theta float64) float64 {
+		r := math.Max(0, radius+b*theta)
+		return 1 / math.Max(math.Hypot(r, b), 1e-9)
+	}
+	table := func(dir float64) []float64 {
+		angles := make([]float64, steps+2)
+		for i := 1; i < len(angles); i++ {
+			theta := angles[i-1]
+			mid := theta + dir*h/2*rate(theta)
+			angles[i] = theta + dir*h*rate(mid)
+		}
+		return angles
+	}
+	forward, backward := table(1), table(-1)
+	angleAt := func(s float64) float64 {
+		if h == 0 {
+			return 0
+		}
+		angles := forward
+		if s < 0 {
+			angles, s = backward, -s
+		}
+		i := math.Min(math.Floor(s/h), steps)
+		return blmath.Lerp(s/h-i, angles[int(i)], angles[int(i)+1])
+	}
+
+	for _, point := range p {
+		theta := angleAt(point.X)
+		r := math.Max(0, radius+b*theta) - point.Y
+		wrapped := NewPoint(math.Sin(theta)*r, radius-math.Cos(theta)*r, point.Z)
+		point.Lerp(t, wrapped)
+	}
+}
+
 // GetSize returns the width, depth and height of a point list.
 func (p PointList) GetSize() (float64, float64, float64) {
 	minX, minY, minZ := math.MaxFloat64, math.MaxFloat64, math.MaxFloat64
@@ -301,6 +650,18 @@ func (p PointList) GetSize() (float64, float64, float64) {
 	return maxX - minX, maxY - minY, maxZ - minZ
 }
 
+// centroid returns the average position of all the points in the list.
+func (p PointList) centroid() (float64, float64, float64) {
+	x, y, z := 0.0, 0.0, 0.0
+	for _, point := range p {
+		x += point.X
+		y += point.Y
+		z += point.Z
+	}
+	count := float64(len(p))
+	return x / count, y / count, z / count
+}
+
 // Center centers the point list on all axes.
 func (p PointList) Center() {
 	minX, minY, minZ := math.MaxFloat64, math.MaxFloat64, math.MaxFloat64
@@ -355,30 +716,91 @@ func (p PointList) Translate(tx, ty, tz float64) {
 
 // RotateX rotates each point in this pointlist around the x-axis, in place.
 func (p PointList) RotateX(angle float64) {
-	for _, point := range p {
-		point.RotateX(angle)
-	}
+	c := math.Cos(angle)
+	s := math.Sin(angle)
+	p.batch(func(list PointList) {
+		for _, point := range list {
+			y := c*point.Y + s*point.Z
+			point.Z = c*point.Z - s*point.Y
+			point.Y = y
+		}
+	})
 }
 
 // RotateY rotates each point in this pointlist around the y-axis, in place.
 func (p PointList) RotateY(angle float64) {
-	for _, point := range p {
-		point.RotateY(angle)
-	}
+	c := math.Cos(angle)
+	s := math.Sin(angle)
+	p.batch(func(list PointList) {
+		for _, point := range list {
+			x := c*point.X + s*point.Z
+			point.Z = c*point.Z - s*point.X
+			point.X = x
+		}
+	})
 }
 
 // RotateZ rotates each point in this pointlist around the z-axis, in place.
 func (p PointList) RotateZ(angle float64) {
-	for _, point := range p {
-		point.RotateZ(angle)
-	}
+	c := math.Cos(angle)
+	s := math.Sin(angle)
+	p.batch(func(list PointList) {
+		for _, point := range list {
+			y := c*point.Y + s*point.X
+			point.X = c*point.X - s*point.Y
+			point.Y = y
+		}
+	})
 }
 
 // Rotate rotates each point in this pointlist around all axes, in place.
+// The three rotations are combined into a single matrix, so each point is only transformed once.
 func (p PointList) Rotate(rx, ry, rz float64) {
-	for _, point := range p {
-		point.Rotate(rx, ry, rz)
+	// the columns of the matrix are the rotated axes.
+	ax := NewPoint(1, 0, 0).Rotated(rx, ry, rz)
+	ay := NewPoint(0, 1, 0).Rotated(rx, ry, rz)
+	az := NewPoint(0, 0, 1).Rotated(rx, ry, rz)
+	p.batch(func(list PointList) {
+		for _, point := range list {
+			x, y, z := point.X, point.Y, point.Z
+			point.X = ax.X*x + ay.X*y + az.X*z
+			point.Y = ax.Y*x + ay.Y*y + az.Y*z
+			point.Z = ax.Z*x + ay.Z*y + az.Z*z
+		}
+	})
+}
+
+// RotateAxis rotates each point in this pointlist around an arbitrary axis through the origin, in place.
+// See Point.RotateAxis.
+func (p PointList) RotateAxis(axis *Point, angle float64) {
+	// the columns of the matrix are the rotated axes.
+	ax := NewPoint(1, 0, 0).RotatedAxis(axis, angle)
+	ay := NewPoint(0, 1, 0).RotatedAxis(axis, angle)
+	az := NewPoint(0, 0, 1).RotatedAxis(axis, angle)
+	p.batch(func(list PointList) {
+		for _, point := range list {
+			x, y, z := point.X, point.Y, point.Z
+			point.X = ax.X*x + ay.X*y + az.X*z
+			point.Y = ax.Y*x + ay.Y*y + az.Y*z
+			point.Z = ax.Z*x + ay.Z*y + az.Z*z
+		}
+	})
+}
+
+// RotateAround rotates each point in this pointlist around all axes, centered on the given pivot, in place.
+func (p PointList) RotateAround(pivot *Point, rx, ry, rz float64) {
+	p.Transform(IdentityMatrix().Translate(-pivot.X, -pivot.Y, -pivot.Z).Rotate(rx, ry, rz).Translate(pivot.X, pivot.Y, pivot.Z))
+}
+
+// batch calls f on sections of this list. Large lists are split across the world's workers (see Parallel).
+func (p PointList) batch(f func(list PointList)) {
+	if len(p) < 65536 {
+		f(p)
+		return
 	}
+	parallelRange(len(p), func(start, end int) {
+		f(p[start:end])
+	})
 }
 
 // ScaleX scales each point in this pointlist on the x-axis, in place.
@@ -416,6 +838,45 @@ func (p PointList) UniScale(scale float64) {
 	}
 }
 
+// ScaleAround scales each point in this pointlist by the same amount on each axis, centered on the given pivot, in place.
+func (p PointList) ScaleAround(pivot *Point, scale float64) {
+	p.Transform(IdentityMatrix().Translate(-pivot.X, -pivot.Y, -pivot.Z).UniScale(scale).Translate(pivot.X, pivot.Y, pivot.Z))
+}
+
+// Stretch scales each point in this pointlist by amount along the given axis, and inversely on the other two axes,
+// so the volume stays the same, in place. Amounts above 1 stretch and below 1 squash.
+func (p PointList) Stretch(axis Axis, amount float64) {
+	p.Scale(stretchScales(axis, amount))
+}
+
+// ShearXY shears each point in this pointlist on the xy plane, in place.
+func (p PointList) ShearXY(amount float64) {
+	for _, point := range p {
+		point.ShearXY(amount)
+	}
+}
+
+// ShearXZ shears each point in this pointlist on the xz plane, in place.
+func (p PointList) ShearXZ(amount float64) {
+	for _, point := range p {
+		point.ShearXZ(amount)
+	}
+}
+
+// ShearYZ shears each point in this pointlist on the yz plane, in place.
+func (p PointList) ShearYZ(amount float64) {
+	for _, point := range p {
+		point.ShearYZ(amount)
+	}
+}
+
+// Shear shears each point in this pointlist on all three planes, in place.
+func (p PointList) Shear(xy, xz, yz float64) {
+	for _, point := range p {
+		point.Shear(xy, xz, yz)
+	}
+}
+
 // RandomizeX randomizes each point in this pointlist on the x-axis, in place.
 func (p PointList) RandomizeX(amount float64) {
 	for _, point := range p {
@@ -444,19 +905,75 @@ func (p PointList) Randomize(amount float64) {
 	}
 }
 
-// Push pushes points away from the specified point.
+// RandomizeAxes randomizes each point in this pointlist by a separate amount on each axis,
+// using the given distribution, in place.
+func (p PointList) RandomizeAxes(ax, ay, az float64, dist RandomDistribution) {
+	for _, point := range p {
+		point.RandomizeAxes(ax, ay, az, dist)
+	}
+}
+
+// Push pushes points within radius of the pusher out to the edge of the radius, away from it.
+// See PushWith for other falloffs and pusher shapes.
 func (p PointList) Push(pusher *Point, radius float64) {
+	p.PushWith(pusher, radius, FalloffHard)
+}
+
+// RandomizeRadial moves each point in this pointlist toward or away from the centroid of the list
+// by a random amount of up to amount, in place. Points only move along their direction from the centroid,
+// so the outline of a shape stays crisp while its surface gets rougher. Points at the centroid are not moved.
+func (p PointList) RandomizeRadial(amount float64) {
+	cx, cy, cz := p.centroid()
+	for _, point := range p {
+		dir := NewPoint(point.X-cx, point.Y-cy, point.Z-cz)
+		mag := dir.Magnitude()
+		if mag == 0 {
+			continue
+		}
+		d := random.FloatRange(-amount, amount) / mag
+		point.Translate(dir.X*d, dir.Y*d, dir.Z*d)
+	}
+}
+
+// RandomizeTangential moves each point in this pointlist by a random amount of up to amount on each axis,
+// in place, but only across its direction from the centroid of the list, never toward or away from it.
+// Points slide around the surface of a shape, adding texture without changing its silhouette much.
+// Points at the centroid move in any direction.
+func (p PointList) RandomizeTangential(amount float64) {
+	cx, cy, cz := p.centroid()
 	for _, point := range p {
-		dist := point.Distance(pusher)
-		if dist < radius {
-			point.X -= pusher.X
-			point.Y -= pusher.Y
-			point.Z -= pusher.Z
-			point.UniScale(radius / dist)
-			point.X += pusher.X
-			point.Y += pusher.Y
-			point.Z += pusher.Z
+		offset := NewPoint(
+			random.FloatRange(-amount, amount),
+			random.FloatRange(-amount, amount),
+			random.FloatRange(-amount, amount),
+		)
+		dir := NewPoint(point.X-cx, point.Y-cy, point.Z-cz)
+		if mag := dir.Magnitude(); mag > 0 {
+			// remove the radial part of the offset.
+			dot := (offset.X*dir.X + offset.Y*dir.Y + offset.Z*dir.Z) / (mag * mag)
+			offset.Translate(-dir.X*dot, -dir.Y*dot, -dir.Z*dot)
 		}
+		point.Translate(offset.X, offset.Y, offset.Z)
+	}
+}
+
+// RandomizeLooped moves each point in this pointlist by a smoothly wandering random offset of up to amount
+// on each axis, in place. The offsets change smoothly with t, and repeat exactly every period,
+// so calling this on a fresh copy each frame gives a wobble that loops seamlessly, unlike Randomize.
+// Each point's offset depends on its index in the list, so points should stay in the same order from frame to frame.
+// A period of 0 or less gives a fixed offset that does not change with t.
+func (p PointList) RandomizeLooped(amount, t, period float64) {
+	// each point follows its own circle through the noise field, once per period.
+	angle := 0.0
+	if period > 0 {
+		angle = t / period * blmath.Tau
+	}
+	cx, cy := math.Cos(angle), math.Sin(angle)
+	for i, point := range p {
+		offset := float64(i) * 7.31
+		point.X += noise.Simplex3(offset+cx, cy, 0) * amount
+		point.Y += noise.Simplex3(offset+cx, cy, 100) * amount
+		point.Z += noise.Simplex3(offset+cx, cy, 200) * amount
 	}
 }
 
@@ -539,6 +1056,20 @@ func (p PointList) Rotated(rx, ry, rz float64) PointList {
 	return p1
 }
 
+// RotatedAxis returns a copy of this pointlist, rotated around an arbitrary axis through the origin.
+func (p PointList) RotatedAxis(axis *Point, angle float64) PointList {
+	p1 := p.Clone()
+	p1.RotateAxis(axis, angle)
+	return p1
+}
+
+// RotatedAround returns a copy of this pointlist, rotated on all axes around the given pivot.
+func (p PointList) RotatedAround(pivot *Point, rx, ry, rz float64) PointList {
+	p1 := p.Clone()
+	p1.RotateAround(pivot, rx, ry, rz)
+	return p1
+}
+
 // ScaledX returns a copy of this pointlist, scaled on the x-axis.
 func (p PointList) ScaledX(scale float64) PointList {
 	p1 := p.Clone()
@@ -574,6 +1105,48 @@ func (p PointList) UniScaled(scale float64) PointList {
 	return p1
 }
 
+// ScaledAround returns a copy of this pointlist, scaled by the same amount on each axis around the given pivot.
+func (p PointList) ScaledAround(pivot *Point, scale float64) PointList {
+	p1 := p.Clone()
+	p1.ScaleAround(pivot, scale)
+	return p1
+}
+
+// Stretched returns a copy of this pointlist, stretched along the given axis, preserving its volume.
+func (p PointList) Stretched(axis Axis, amount float64) PointList {
+	p1 := p.Clone()
+	p1.Stretch(axis, amount)
+	return p1
+}
+
+// ShearedXY returns a copy of this pointlist, sheared on the xy plane.
+func (p PointList) ShearedXY(amount float64) PointList {
+	p1 := p.Clone()
+	p1.ShearXY(amount)
+	return p1
+}
+
+// ShearedXZ returns a copy of this pointlist, sheared on the xz plane.
+func (p PointList) ShearedXZ(amount float64) PointList {
+	p1 := p.Clone()
+	p1.ShearXZ(amount)
+	return p1
+}
+
+// ShearedYZ returns a copy of this pointlist, sheared on the yz plane.
+func (p PointList) ShearedYZ(amount float64) PointList {
+	p1 := p.Clone()
+	p1.ShearYZ(amount)
+	return p1
+}
+
+// Sheared returns a copy of this pointlist, sheared on all three planes.
+func (p PointList) Sheared(xy, xz, yz float64) PointList {
+	p1 := p.Clone()
+	p1.Shear(xy, xz, yz)
+	return p1
+}
+
 // RandomizedX returns a copy of this pointlist, randomized on the x-axis
 func (p PointList) RandomizedX(amount float64) PointList {
 	p1 := p.Clone()
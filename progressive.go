@@ -0,0 +1,149 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// EdgeCollapse is one step in simplifying a shape: the point at index From is merged into the point at index To,
+// and every segment that used From uses To instead.
+type EdgeCollapse struct {
+	From, To int
+}
+
+// collapseEdge is an edge waiting to be collapsed, with its length.
+type collapseEdge struct {
+	a, b   int
+	length float64
+}
+
+// collapseQueue is a min-heap of edges, shortest first. Edges of the same length are taken in order of their points,
+// so the order is the same on every run.
+type collapseQueue []collapseEdge
+
+func (q collapseQueue) Len() int { return len(q) }
+func (q collapseQueue) Less(i, j int) bool {
+	if q[i].length != q[j].length {
+		return q[i].length < q[j].length
+	}
+	if q[i].a != q[j].a {
+		return q[i].a < q[j].a
+	}
+	return q[i].b < q[j].b
+}
+func (q collapseQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *collapseQueue) Push(x any)   { *q = append(*q, x.(collapseEdge)) }
+func (q *collapseQueue) Pop() any {
+	old := *q
+	edge := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return edge
+}
+
+// ProgressiveOrder works out the order in which the shape's segments can be collapsed, shortest first,
+// until each connected part of the shape is down to a single point. Run backward, it rebuilds the shape
+// from coarse to fine, which is what StrokeProgressive draws. When an edge collapses, the point with fewer
+// neighbors is merged into the other, which keeps its position, so the coarse shapes are made of the shape's own points.
+//
+// The order is kept by the shape for StrokeProgressive, so call this again if the shape's segments change.
+// Points may be moved freely, as the order only uses their indices.
+func (s *Shape) ProgressiveOrder() []EdgeCollapse {
+	s.ApplyTransform()
+	index := map[*Point]int{}
+	for i, p := range s.Points {
+		index[p] = i
+	}
+	neighbors := make([]map[int]bool, len(s.Points))
+	for i := range neighbors {
+		neighbors[i] = map[int]bool{}
+	}
+	queue := collapseQueue{}
+	push := func(a, b int) {
+		heap.Push(&queue, collapseEdge{a, b, s.Points[a].Distance(s.Points[b])})
+	}
+	for _, seg := range s.Segments {
+		a, b := index[seg.PointA], index[seg.PointB]
+		if a == b || neighbors[a][b] {
+			continue
+		}
+		neighbors[a][b] = true
+		neighbors[b][a] = true
+		push(a, b)
+	}
+
+	collapses := []EdgeCollapse{}
+	for queue.Len() > 0 {
+		edge := heap.Pop(&queue).(collapseEdge)
+		// edges whose points have since been merged away are stale.
+		if !neighbors[edge.a][edge.b] {
+			continue
+		}
+		from, to := edge.a, edge.b
+		if len(neighbors[from]) > len(neighbors[to]) || (len(neighbors[from]) == len(neighbors[to]) && from < to) {
+			from, to = to, from
+		}
+		merging := make([]int, 0, len(neighbors[from]))
+		for n := range neighbors[from] {
+			merging = append(merging, n)
+		}
+		slices.Sort(merging)
+		for _, n := range merging {
+			delete(neighbors[n], from)
+			if n != to && !neighbors[to][n] {
+				neighbors[to][n] = true
+				neighbors[n][to] = true
+				push(to, n)
+			}
+		}
+		neighbors[from] = nil
+		collapses = append(collapses, EdgeCollapse{from, to})
+	}
+	s.progressive = collapses
+	return collapses
+}
+
+// StrokeProgressive strokes the shape part way through being rebuilt from its coarsest form,
+// from 0 (nothing) to 1 (the full shape). Points are split back out of the points they were merged into
+// in the reverse of the order from ProgressiveOrder, so the overall form appears first and detail fills in,
+// rather than each segment being drawn on. It suits dense meshes, where drawing on is slow to read.
+// The order is worked out the first time, if ProgressiveOrder has not been called.
+func (s *Shape) StrokeProgressive(width, t float64) {
+	if s.progressive == nil {
+		s.ProgressiveOrder()
+	}
+	s.ApplyTransform()
+	total := len(s.progressive)
+	collapsed := total - int(float64(total)*min(max(t, 0), 1)+0.5)
+	merged := map[int]int{}
+	for _, c := range s.progressive[:collapsed] {
+		merged[c.From] = c.To
+	}
+	index := map[*Point]int{}
+	for i, p := range s.Points {
+		index[p] = i
+	}
+	find := func(p *Point) int {
+		i := index[p]
+		for {
+			to, ok := merged[i]
+			if !ok {
+				return i
+			}
+			i = to
+		}
+	}
+
+	coarse := &Shape{ColorAttr: s.ColorAttr, Palette: s.Palette, Points: s.Points}
+	drawn := map[[2]int]bool{}
+	for _, seg := range s.Segments {
+		a, b := find(seg.PointA), find(seg.PointB)
+		key := [2]int{min(a, b), max(a, b)}
+		if a == b || drawn[key] {
+			continue
+		}
+		drawn[key] = true
+		coarse.Segments = append(coarse.Segments, &Segment{s.Points[a], s.Points[b], seg.Width})
+	}
+	coarse.Stroke(width)
+}
@@ -0,0 +1,143 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// Quaternion represents a 3d orientation. Unlike the separate x, y and z angles used by Rotate,
+// orientations can be combined and smoothly interpolated (see Slerp) without gimbal lock.
+// Quaternions used for rotation should be of unit length. The constructors here all produce unit quaternions.
+type Quaternion struct {
+	W, X, Y, Z float64
+}
+
+// IdentityQuaternion returns a quaternion representing no rotation.
+func IdentityQuaternion() Quaternion {
+	return Quaternion{1, 0, 0, 0}
+}
+
+// QuaternionFromAxisAngle creates a quaternion representing a rotation around the given axis.
+// The axis does not need to be normalized. Rotating by it is the same as RotateAxis.
+func QuaternionFromAxisAngle(axis *Point, angle float64) Quaternion {
+	k := axis.Normalized()
+	s := math.Sin(angle / 2)
+	return Quaternion{math.Cos(angle / 2), k.X * s, k.Y * s, k.Z * s}
+}
+
+// QuaternionFromEuler creates a quaternion representing rotations around the x, y and z axes.
+// Rotating by it is the same as Rotate(rx, ry, rz).
+func QuaternionFromEuler(rx, ry, rz float64) Quaternion {
+	// RotateX turns the opposite way to RotateAxis around the x-axis, hence -rx.
+	qx := QuaternionFromAxisAngle(NewPoint(1, 0, 0), -rx)
+	qy := QuaternionFromAxisAngle(NewPoint(0, 1, 0), ry)
+	qz := QuaternionFromAxisAngle(NewPoint(0, 0, 1), rz)
+	return qz.Multiply(qy).Multiply(qx)
+}
+
+// Multiply returns the product of this quaternion and another.
+// Rotating by the result is the same as rotating by r, then by q.
+func (q Quaternion) Multiply(r Quaternion) Quaternion {
+	return Quaternion{
+		q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+		q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W,
+	}
+}
+
+// Conjugate returns the conjugate of this quaternion. For a unit quaternion, this is the opposite rotation.
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{q.W, -q.X, -q.Y, -q.Z}
+}
+
+// Length returns the length of this quaternion.
+func (q Quaternion) Length() float64 {
+	return math.Sqrt(q.W*q.W + q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+}
+
+// Normalized returns this quaternion scaled to unit length.
+// Useful for correcting drift after many multiplications.
+func (q Quaternion) Normalized() Quaternion {
+	l := q.Length()
+	if l == 0 {
+		return IdentityQuaternion()
+	}
+	return Quaternion{q.W / l, q.X / l, q.Y / l, q.Z / l}
+}
+
+// Slerp returns the spherical linear interpolation between this orientation (t = 0) and another (t = 1),
+// turning at a constant rate along the shortest path between them.
+func (q Quaternion) Slerp(r Quaternion, t float64) Quaternion {
+	dot := q.W*r.W + q.X*r.X + q.Y*r.Y + q.Z*r.Z
+	// q and -q are the same orientation. use whichever is closer, to take the short way around.
+	if dot < 0 {
+		r = Quaternion{-r.W, -r.X, -r.Y, -r.Z}
+		dot = -dot
+	}
+	a, b := 1-t, t
+	// very close orientations would divide by almost zero. a plain lerp is accurate enough there.
+	if dot < 0.9995 {
+		angle := math.Acos(dot)
+		sin := math.Sin(angle)
+		a = math.Sin((1-t)*angle) / sin
+		b = math.Sin(t*angle) / sin
+	}
+	return Quaternion{
+		q.W*a + r.W*b,
+		q.X*a + r.X*b,
+		q.Y*a + r.Y*b,
+		q.Z*a + r.Z*b,
+	}.Normalized()
+}
+
+// RotateQuat rotates this point by the given quaternion, in place.
+func (p *Point) RotateQuat(q Quaternion) {
+	// v' = v + 2w(u x v) + 2u x (u x v), where u is the vector part of q.
+	cx := q.Y*p.Z - q.Z*p.Y
+	cy := q.Z*p.X - q.X*p.Z
+	cz := q.X*p.Y - q.Y*p.X
+	p.X += 2 * (q.W*cx + q.Y*cz - q.Z*cy)
+	p.Y += 2 * (q.W*cy + q.Z*cx - q.X*cz)
+	p.Z += 2 * (q.W*cz + q.X*cy - q.Y*cx)
+}
+
+// RotatedQuat returns a copy of this point, rotated by the given quaternion.
+func (p *Point) RotatedQuat(q Quaternion) *Point {
+	p1 := p.Clone()
+	p1.RotateQuat(q)
+	return p1
+}
+
+// RotateQuat rotates each point in this pointlist by the given quaternion, in place.
+func (p PointList) RotateQuat(q Quaternion) {
+	// the columns of the matrix are the rotated axes.
+	ax := NewPoint(1, 0, 0).RotatedQuat(q)
+	ay := NewPoint(0, 1, 0).RotatedQuat(q)
+	az := NewPoint(0, 0, 1).RotatedQuat(q)
+	p.batch(func(list PointList) {
+		for _, point := range list {
+			x, y, z := point.X, point.Y, point.Z
+			point.X = ax.X*x + ay.X*y + az.X*z
+			point.Y = ax.Y*x + ay.Y*y + az.Y*z
+			point.Z = ax.Z*x + ay.Z*y + az.Z*z
+		}
+	})
+}
+
+// RotatedQuat returns a copy of this pointlist, rotated by the given quaternion.
+func (p PointList) RotatedQuat(q Quaternion) PointList {
+	p1 := p.Clone()
+	p1.RotateQuat(q)
+	return p1
+}
+
+// RotateQuat rotates this shape by the given quaternion, in place.
+func (s *Shape) RotateQuat(q Quaternion) {
+	s.transformTarget().RotateQuat(q)
+}
+
+// RotatedQuat returns a copy of this shape, rotated by the given quaternion.
+func (s *Shape) RotatedQuat(q Quaternion) *Shape {
+	s1 := s.Clone()
+	s1.RotateQuat(q)
+	return s1
+}
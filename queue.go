@@ -0,0 +1,81 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/bit101/bitlib/blcolor"
+)
+
+// queued is a segment or point waiting in the render queue, with the depth it is sorted by.
+type queued struct {
+	depth float64
+	draw  func()
+}
+
+// renderQueue holds everything queued since the last call to Render.
+var renderQueue []queued
+
+// Queue adds the segments of the shape to the render queue, to be stroked with the given width by Render.
+// Segments from every queued shape are drawn together from back to front, so shapes that intersect or surround
+// each other interleave correctly, rather than whichever is stroked last painting over the other.
+// The shape's current position and colors are used, so it can be changed or queued again after this.
+// Segments hidden by backface culling are left out. Arcs are not queued.
+func (s *Shape) Queue(width float64) {
+	s.ApplyTransform()
+	s.Points.project(s.boilID())
+	min, max := s.Points.AttrRange(s.ColorAttr)
+	for _, segment := range s.drawOrder() {
+		seg := &Segment{segment.PointA.Clone(), segment.PointB.Clone(), segment.Width}
+		var colorA, colorB *blcolor.Color
+		if s.ColorAttr != "" {
+			a := s.Palette.Color(s.attrPosition(segment.PointA, min, max))
+			b := s.Palette.Color(s.attrPosition(segment.PointB, min, max))
+			colorA, colorB = &a, &b
+		}
+		renderQueue = append(renderQueue, queued{
+			depth: (seg.PointA.Z + seg.PointB.Z) / 2,
+			draw:  func() { seg.strokeGradient(width, colorA, colorB) },
+		})
+	}
+}
+
+// QueuePoints adds the points of the shape to the render queue, to be drawn as filled circles of the given radius
+// by Render, sorted along with queued segments.
+func (s *Shape) QueuePoints(radius float64) {
+	s.ApplyTransform()
+	s.Points.project(s.boilID())
+	min, max := s.Points.AttrRange(s.ColorAttr)
+	points := s.Points.Clone()
+	// density alpha depends on the neighbors of each point, so it is worked out over the whole shape now.
+	density := points.densityAlpha()
+	for i, point := range points {
+		var color *blcolor.Color
+		if s.ColorAttr != "" {
+			c := s.Palette.Color(s.attrPosition(s.Points[i], min, max))
+			color = &c
+		}
+		renderQueue = append(renderQueue, queued{
+			depth: point.Z,
+			draw:  func() { renderPoint(point, i, radius, color, density[i]) },
+		})
+	}
+}
+
+// Render draws everything in the render queue from back to front, then empties the queue.
+// Items at the same depth are drawn in the order they were queued. See Shape.Queue.
+func Render() {
+	slices.SortStableFunc(renderQueue, func(a, b queued) int {
+		return cmp.Compare(b.depth, a.depth)
+	})
+	for _, item := range renderQueue {
+		item.draw()
+	}
+	ClearQueue()
+}
+
+// ClearQueue empties the render queue without drawing anything.
+func ClearQueue() {
+	renderQueue = nil
+}
@@ -0,0 +1,85 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math"
+
+	"github.com/bit101/bitlib/blcolor"
+)
+
+// PaletteRain is a palette for DigitalRain, running from white at the head of each stream,
+// through bright and dark green, to transparent at the end of its trail.
+var PaletteRain = NewPalette(
+	blcolor.RGB(0.9, 1, 0.9),
+	blcolor.RGB(0.2, 1, 0.3),
+	blcolor.RGB(0, 0.5, 0.1),
+	blcolor.RGBA(0, 0.2, 0, 0),
+)
+
+// rainGlyphs are the characters DigitalRain draws from.
+const rainGlyphs = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789$+-*=<>?#%&"
+
+// rainTrail is the name of the point attribute DigitalRain sets, from 0 at the head of each stream to 1 at its tail.
+const rainTrail = "trail"
+
+// DigitalRain creates a frame of falling columns of glyphs, in the current font, like the digital rain of
+// computer screens in the movies. There are cols columns, spaced by the font size and spacing,
+// centered on the origin, falling down the screen through a region of the given height.
+// Each column has its own speed and trail length, and its glyphs flicker from one to another over time.
+// The result depends only on its arguments, so calling it with the time of each frame gives a smooth animation.
+//
+// Each point has a "trail" attribute, from 0 at the head of its stream to 1 at the end of its trail,
+// and the shape is colorized with PaletteRain so the trails fade out. Call ColorizeByAttr to use other colors.
+func DigitalRain(cols int, height float64, t float64) *Shape {
+	shape := NewShape()
+	glyphs := []rune(rainGlyphs)
+	colWidth := world.FontSize * (1 + world.FontSpacing)
+	rowHeight := world.FontSize*world.Font.height/world.Font.width + world.FontSize*world.FontSpacing
+	rows := max(1, int(height/rowHeight))
+	left := -colWidth * float64(cols-1) / 2
+	top := -rowHeight * float64(rows-1) / 2
+
+	// random returns a value from 0 to 1 that is always the same for the same inputs.
+	random := func(values ...int) float64 {
+		h := uint64(0)
+		for _, v := range values {
+			h = mix64(h ^ uint64(v))
+		}
+		return float64(h>>11) / (1 << 53)
+	}
+
+	for col := range cols {
+		speed := 6 + random(col, 0)*14
+		trail := 4 + int(random(col, 1)*float64(rows))
+		// the head falls from above the region until the whole trail has left the bottom, then starts again.
+		cycle := float64(rows + trail)
+		pos := math.Mod(t*speed+random(col, 2)*cycle, cycle)
+		head := int(pos)
+		// the stream gets new random values each time it starts again.
+		pass := int(math.Floor((t*speed + random(col, 2)*cycle) / cycle))
+		for k := range trail {
+			row := head - k
+			if row < 0 || row >= rows {
+				continue
+			}
+			// glyphs change now and then, the head on every step.
+			step := int(math.Floor(t * (0.5 + random(col, row, 3)*2)))
+			if k == 0 {
+				step = int(math.Floor(t * speed))
+			}
+			index := int(random(col, row, pass, step) * float64(len(glyphs)))
+			glyph := ParseChar(string(glyphs[index]), world.Font)
+			if random(col, row, pass, step, 4) < 0.5 {
+				glyph.ScaleX(-1)
+			}
+			fade := (float64(k) + pos - float64(head)) / float64(trail)
+			for _, p := range glyph.Points {
+				p.SetAttr(rainTrail, math.Min(1, fade))
+			}
+			glyph.Translate(left+colWidth*float64(col), top+rowHeight*float64(row), 0)
+			shape.AddShape(glyph)
+		}
+	}
+	shape.ColorizeByAttr(rainTrail, PaletteRain)
+	return shape
+}
@@ -0,0 +1,144 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"errors"
+	"maps"
+
+	"github.com/bit101/bitlib/blmath"
+)
+
+// Recipe records the primitive constructor a shape was made with, and the parameters passed to it, by name.
+// Parameter names match the constructor's arguments. Ints are stored as floats, and bools as 1 or 0.
+// Shapes built by primitive constructors such as Box, Sphere and Torus have a recipe, so they can be rebuilt
+// later with different parameters, such as a higher resolution. See Shape.Rebuild.
+// Shape methods that add or remove points, segments or faces, such as AddXYZ, AddShape, Subdivide and Cull,
+// clear the recipe. Changing the Points, Segments or Faces fields directly does not.
+type Recipe struct {
+	Primitive string
+	Params    map[string]float64
+}
+
+// RecipeParam is a single named parameter, used to override part of a recipe when rebuilding a shape.
+type RecipeParam struct {
+	Name  string
+	Value float64
+}
+
+// boolParam returns a bool as a recipe parameter.
+func boolParam(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Rebuild returns a new shape made by the same primitive constructor as this shape, with the same parameters
+// apart from the given overrides, such as:
+//
+//	torus, err = torus.Rebuild(wire.RecipeParam{Name: "res", Value: 48})
+//
+// The new shape is built from scratch, so any transforms or other changes made to this shape are not carried over.
+// Returns an error if the shape has no recipe, or an override names a parameter the primitive doesn't have.
+func (s *Shape) Rebuild(overrides ...RecipeParam) (*Shape, error) {
+	if s.Recipe == nil {
+		return nil, errors.New("unable to rebuild shape: shape has no recipe")
+	}
+	create, ok := primitives[s.Recipe.Primitive]
+	if !ok {
+		return nil, errors.New("unable to rebuild shape: unknown primitive " + s.Recipe.Primitive)
+	}
+	params := maps.Clone(s.Recipe.Params)
+	for _, o := range overrides {
+		if _, ok := params[o.Name]; !ok {
+			return nil, errors.New("unable to rebuild shape: " + s.Recipe.Primitive + " has no parameter " + o.Name)
+		}
+		params[o.Name] = o.Value
+	}
+	return create(params), nil
+}
+
+// primitiveParams holds the named parameters of a primitive. Ints are truncated and bools are true if not zero.
+type primitiveParams map[string]float64
+
+func (p primitiveParams) get(name string, def float64) float64 {
+	if v, ok := p[name]; ok {
+		return v
+	}
+	return def
+}
+
+func (p primitiveParams) getInt(name string, def int) int {
+	return int(p.get(name, float64(def)))
+}
+
+func (p primitiveParams) getBool(name string, def bool) bool {
+	if v, ok := p[name]; ok {
+		return v != 0
+	}
+	return def
+}
+
+// primitives creates the primitive shapes that can be rebuilt from a recipe or named in a scene file.
+// Any parameter that is left out uses its default value.
+var primitives = map[string]func(p primitiveParams) *Shape{
+	"box": func(p primitiveParams) *Shape {
+		return Box(p.get("w", 100), p.get("h", 100), p.get("d", 100))
+	},
+	"circle": func(p primitiveParams) *Shape {
+		return Circle(p.get("radius", 100), p.getInt("res", 36))
+	},
+	"cone": func(p primitiveParams) *Shape {
+		return Cone(p.get("height", 200), p.get("radius0", 100), p.get("radius1", 0),
+			p.getInt("slices", 8), p.getInt("res", 24), p.getBool("showSlices", true), p.getBool("showLong", true))
+	},
+	"cylinder": func(p primitiveParams) *Shape {
+		return Cylinder(p.get("height", 200), p.get("radius", 100),
+			p.getInt("slices", 8), p.getInt("res", 24), p.getBool("showSlices", true), p.getBool("showLong", true))
+	},
+	"gridbox": func(p primitiveParams) *Shape {
+		return GridBox(p.get("w", 100), p.get("h", 100), p.get("d", 100),
+			p.getInt("xCount", 5), p.getInt("yCount", 5), p.getInt("zCount", 5), p.getBool("inner", false))
+	},
+	"gridplane": func(p primitiveParams) *Shape {
+		return GridPlane(p.get("w", 200), p.get("d", 200), p.getInt("rows", 10), p.getInt("cols", 10))
+	},
+	"asteroid": func(p primitiveParams) *Shape {
+		return Asteroid(p.get("radius", 100), p.get("irregularity", 0.3), p.getInt("subdivisions", 2), int64(p.get("seed", 0)))
+	},
+	"icosphere": func(p primitiveParams) *Shape {
+		return Icosphere(p.get("radius", 100), p.getInt("subdivisions", 2))
+	},
+	"pyramid": func(p primitiveParams) *Shape {
+		return Pyramid(p.get("height", 100), p.get("baseRadius", 100), p.getInt("sides", 4))
+	},
+	"sphere": func(p primitiveParams) *Shape {
+		return Sphere(p.get("radius", 100), p.getInt("long", 12), p.getInt("lat", 24),
+			p.getBool("showLong", true), p.getBool("showLat", true))
+	},
+	"spring": func(p primitiveParams) *Shape {
+		return Spring(p.get("height", 200), p.get("r0", 100), p.get("r1", 100), p.get("turns", 5), p.get("res", 36))
+	},
+	"torus": func(p primitiveParams) *Shape {
+		return Torus(p.get("r1", 100), p.get("r2", 30), p.get("arc", blmath.Tau),
+			p.getInt("slices", 12), p.getInt("res", 24), p.getBool("showSlices", true), p.getBool("showLong", true))
+	},
+	"torusknot": func(p primitiveParams) *Shape {
+		return TorusKnot(p.get("p", 2), p.get("q", 3), p.get("r1", 100), p.get("r2", 40), p.get("res", 0.01))
+	},
+	"tetrahedron": func(p primitiveParams) *Shape {
+		return Tetrahedron(p.get("size", 100))
+	},
+	"cube": func(p primitiveParams) *Shape {
+		return Cube(p.get("size", 100))
+	},
+	"octahedron": func(p primitiveParams) *Shape {
+		return Octahedron(p.get("size", 100))
+	},
+	"dodecahedron": func(p primitiveParams) *Shape {
+		return Dodecahedron(p.get("size", 100))
+	},
+	"icosahedron": func(p primitiveParams) *Shape {
+		return Icosahedron(p.get("size", 100))
+	},
+}
@@ -0,0 +1,411 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bit101/bitlib/blcolor"
+	"github.com/bit101/bitlib/blmath"
+)
+
+//////////////////////////////////////////////////////////////
+// Scenes can be described in a JSON file and loaded with LoadScene.
+// A scene is a list of objects. Each object is either a built in primitive,
+// with optional named parameters, a line of text, or a shape file to import
+// (.obj files, or the format used by Shape.Save). Import paths are relative to
+// the scene file. Rotations, and the arc of a torus, are in degrees.
+// Any parameter that is left out uses its default value. Parameter names match
+// the arguments of the primitive's constructor.
+//
+// Example:
+// {
+//   "objects": [
+//     {
+//       "primitive": "torus",
+//       "params": {"r1": 200, "r2": 50},
+//       "color": [1, 0.5, 0],
+//       "width": 0.5,
+//       "rotate": [45, 0, 0]
+//     },
+//     {
+//       "import": "models/teapot.obj",
+//       "scale": [20, 20, 20],
+//       "keyframes": [
+//         {"time": 0, "rotate": [0, 0, 0]},
+//         {"time": 1, "rotate": [0, 360, 0]}
+//       ]
+//     },
+//     {
+//       "text": "HELLO",
+//       "pointRadius": 2,
+//       "translate": [0, -300, 0]
+//     }
+//   ]
+// }
+//////////////////////////////////////////////////////////////
+
+// Scene is a set of shapes loaded from a scene file, each with its own color, width and animation.
+type Scene struct {
+	Objects   []*SceneObject
+	sectors   int
+	mirror    bool
+	showStats bool
+	safeArea  [2]float64
+}
+
+// SceneObject is a single shape in a scene. Its shape keeps its original coordinates, and each render
+// scales, rotates and translates it, in that order, by the object's transform at the given time.
+// Rotations are in radians. A nil color uses the world's color.
+type SceneObject struct {
+	Shape                    *Shape
+	Color                    *blcolor.Color
+	Width, PointRadius       float64
+	Translate, Rotate, Scale [3]float64
+	Keyframes                []SceneKeyframe
+	dirty                    bool
+	retained                 []*retainedDraw
+}
+
+// retainedDraw is a transformed and projected copy of a scene object, as it was last drawn in one place
+// in the scene, reused until the object, its place or the camera changes.
+type retainedDraw struct {
+	key   retainedKey
+	shape *Shape
+}
+
+// retainedKey is everything that decides where a scene object's points are projected to.
+type retainedKey struct {
+	translate, rotate, scale [3]float64
+	angle                    float64
+	mirror                   bool
+	camera                   [4]float64
+}
+
+// SceneKeyframe is the transform of a scene object at a given time.
+// Between keyframes, the transform is linearly interpolated.
+type SceneKeyframe struct {
+	Time                     float64
+	Translate, Rotate, Scale [3]float64
+}
+
+type sceneFile struct {
+	Objects []sceneObjectDef `json:"objects"`
+}
+
+type sceneObjectDef struct {
+	Primitive   string             `json:"primitive"`
+	Params      map[string]float64 `json:"params"`
+	Text        string             `json:"text"`
+	Import      string             `json:"import"`
+	Color       []float64          `json:"color"`
+	Width       *float64           `json:"width"`
+	PointRadius float64            `json:"pointRadius"`
+	Translate   *[3]float64        `json:"translate"`
+	Rotate      *[3]float64        `json:"rotate"`
+	Scale       *[3]float64        `json:"scale"`
+	Keyframes   []sceneKeyframeDef `json:"keyframes"`
+}
+
+type sceneKeyframeDef struct {
+	Time      float64     `json:"time"`
+	Translate *[3]float64 `json:"translate"`
+	Rotate    *[3]float64 `json:"rotate"`
+	Scale     *[3]float64 `json:"scale"`
+}
+
+// LoadScene loads a scene from a JSON scene file.
+func LoadScene(fileName string) (*Scene, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.New("unable to load scene: " + err.Error())
+	}
+	var file sceneFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, errors.New("unable to load scene: " + err.Error())
+	}
+
+	scene := &Scene{}
+	dir := filepath.Dir(fileName)
+	for i, def := range file.Objects {
+		obj, err := def.object(dir)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load scene: object %d: %s", i, err.Error())
+		}
+		scene.Objects = append(scene.Objects, obj)
+	}
+	return scene, nil
+}
+
+// object creates the scene object described by this definition.
+func (d sceneObjectDef) object(dir string) (*SceneObject, error) {
+	shape, err := d.shape(dir)
+	if err != nil {
+		return nil, err
+	}
+	shape.KeepOriginal()
+
+	obj := &SceneObject{
+		Shape:       shape,
+		Width:       1,
+		PointRadius: d.PointRadius,
+		Translate:   vec3(d.Translate, [3]float64{0, 0, 0}),
+		Rotate:      degreesToRadians(vec3(d.Rotate, [3]float64{0, 0, 0})),
+		Scale:       vec3(d.Scale, [3]float64{1, 1, 1}),
+	}
+	if d.Width != nil {
+		obj.Width = *d.Width
+	}
+	switch len(d.Color) {
+	case 0:
+	case 3:
+		c := blcolor.RGB(d.Color[0], d.Color[1], d.Color[2])
+		obj.Color = &c
+	case 4:
+		c := blcolor.RGBA(d.Color[0], d.Color[1], d.Color[2], d.Color[3])
+		obj.Color = &c
+	default:
+		return nil, errors.New("color must have 3 or 4 values")
+	}
+
+	// keyframe values that are left out use the object's own transform.
+	for _, k := range d.Keyframes {
+		obj.Keyframes = append(obj.Keyframes, SceneKeyframe{
+			Time:      k.Time,
+			Translate: vec3(k.Translate, obj.Translate),
+			Rotate:    degreesToRadians(vec3(k.Rotate, radiansToDegrees(obj.Rotate))),
+			Scale:     vec3(k.Scale, obj.Scale),
+		})
+	}
+	sort.SliceStable(obj.Keyframes, func(i, j int) bool {
+		return obj.Keyframes[i].Time < obj.Keyframes[j].Time
+	})
+	return obj, nil
+}
+
+// shape creates the shape described by this definition.
+func (d sceneObjectDef) shape(dir string) (*Shape, error) {
+	switch {
+	case d.Import != "":
+		path := d.Import
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if strings.EqualFold(filepath.Ext(path), ".obj") {
+			return ShapeFromOBJ(path)
+		}
+		return LoadShape(path)
+	case d.Text != "":
+		return NewString(d.Text).AsLine(), nil
+	case d.Primitive != "":
+		create, ok := primitives[strings.ToLower(d.Primitive)]
+		if !ok {
+			return nil, errors.New("unknown primitive " + d.Primitive)
+		}
+		// arcs are in degrees in scene files, like rotations.
+		params := maps.Clone(d.Params)
+		if arc, ok := params["arc"]; ok {
+			params["arc"] = blmath.DegToRad(arc)
+		}
+		return create(params), nil
+	}
+	return nil, errors.New("object needs a primitive, text or import")
+}
+
+// vec3 returns the value of v, or def if v was left out.
+func vec3(v *[3]float64, def [3]float64) [3]float64 {
+	if v == nil {
+		return def
+	}
+	return *v
+}
+
+func degreesToRadians(v [3]float64) [3]float64 {
+	return [3]float64{blmath.DegToRad(v[0]), blmath.DegToRad(v[1]), blmath.DegToRad(v[2])}
+}
+
+func radiansToDegrees(v [3]float64) [3]float64 {
+	return [3]float64{blmath.RadToDeg(v[0]), blmath.RadToDeg(v[1]), blmath.RadToDeg(v[2])}
+}
+
+// TransformAt returns the translation, rotation and scale of the object at the given time.
+// With no keyframes, this is the object's own transform. Before the first keyframe and after the last,
+// the transform holds at that keyframe.
+func (o *SceneObject) TransformAt(t float64) (translate, rotate, scale [3]float64) {
+	keys := o.Keyframes
+	if len(keys) == 0 {
+		return o.Translate, o.Rotate, o.Scale
+	}
+	if t <= keys[0].Time {
+		return keys[0].Translate, keys[0].Rotate, keys[0].Scale
+	}
+	last := keys[len(keys)-1]
+	if t >= last.Time {
+		return last.Translate, last.Rotate, last.Scale
+	}
+	i := sort.Search(len(keys), func(i int) bool { return keys[i].Time > t })
+	a, b := keys[i-1], keys[i]
+	u := blmath.Map(t, a.Time, b.Time, 0, 1)
+	for j := range 3 {
+		translate[j] = blmath.Lerp(u, a.Translate[j], b.Translate[j])
+		rotate[j] = blmath.Lerp(u, a.Rotate[j], b.Rotate[j])
+		scale[j] = blmath.Lerp(u, a.Scale[j], b.Scale[j])
+	}
+	return translate, rotate, scale
+}
+
+// Render draws the object as it is at the given time.
+func (o *SceneObject) Render(t float64) {
+	o.place(t)
+	o.draw(o.Shape)
+}
+
+// MarkDirty flags the object's shape as changed, such as after its points are moved or segments added,
+// so the next Scene.Render transforms and projects it again rather than drawing its retained copy.
+// Changes to the object's transform or keyframes, or to the camera, are found without this.
+func (o *SceneObject) MarkDirty() {
+	o.dirty = true
+}
+
+// key returns the retained key for the object at the given time and place in the scene.
+func (o *SceneObject) key(t, angle float64, mirror bool) retainedKey {
+	translate, rotate, scale := o.TransformAt(t)
+	return retainedKey{translate, rotate, scale, angle, mirror, [4]float64{world.FL, world.CX, world.CY, world.CZ}}
+}
+
+// stale returns whether the retained copy in the given slot can't be drawn as it is.
+// Boiling lines move every frame, so nothing is retained while boil is on.
+func (o *SceneObject) stale(slot int, key retainedKey) bool {
+	return o.dirty || world.BoilAmplitude != 0 || slot >= len(o.retained) || o.retained[slot] == nil ||
+		o.retained[slot].key != key
+}
+
+// drawRetained draws the object at the given time and place in the scene, from its retained copy in the given slot,
+// making a new copy first if it is stale.
+func (o *SceneObject) drawRetained(t float64, slot int, angle float64, mirror bool) {
+	key := o.key(t, angle, mirror)
+	if o.stale(slot, key) {
+		o.place(t)
+		if mirror {
+			o.Shape.Scale(-1, 1, 1)
+		}
+		o.Shape.RotateZ(angle)
+		shape := o.Shape.Clone()
+		if mirror {
+			// reflecting turns faces inside out, so they are reversed, as with Mirror.
+			for _, face := range shape.Faces {
+				slices.Reverse(face.Points)
+			}
+		}
+		shape.ApplyTransform()
+		shape.Points.project(shape.boilID())
+		shape.projected = true
+		for len(o.retained) <= slot {
+			o.retained = append(o.retained, nil)
+		}
+		o.retained[slot] = &retainedDraw{key, shape}
+	}
+	o.draw(o.retained[slot].shape)
+}
+
+// place sets the object's shape to its transform at the given time.
+func (o *SceneObject) place(t float64) {
+	translate, rotate, scale := o.TransformAt(t)
+	s := o.Shape
+	s.ResetTransform()
+	s.Scale(scale[0], scale[1], scale[2])
+	s.Rotate(rotate[0], rotate[1], rotate[2])
+	s.Translate(translate[0], translate[1], translate[2])
+}
+
+// draw strokes the given shape, the object's shape or a copy of it, in the object's style.
+func (o *SceneObject) draw(s *Shape) {
+	if o.Color == nil {
+		s.Stroke(o.Width)
+		if o.PointRadius > 0 {
+			s.RenderPoints(o.PointRadius)
+		}
+		return
+	}
+	s.strokeColor(o.Width, *o.Color)
+	if o.PointRadius > 0 {
+		highlight(s.Points, o.PointRadius, *o.Color)
+	}
+}
+
+// SetKaleidoscope makes the scene render as a kaleidoscope, with the given number of copies of the scene
+// rotated evenly around the z-axis, so they are spread around the center of the canvas like a mandala.
+// If mirror is true, each copy is joined by a reflection of itself across the y-axis, as between
+// the mirrors of a real kaleidoscope. A sectors value of 1 or less, without mirror, turns the kaleidoscope off.
+func (s *Scene) SetKaleidoscope(sectors int, mirror bool) {
+	s.sectors = max(1, sectors)
+	s.mirror = mirror
+}
+
+// ShowStats sets whether Render draws an overlay in the top left corner of the screen, in the current font,
+// listing the number of segments and points drawn, how many were culled by the clipping planes,
+// and how long the frame took to render. See GetRenderStats.
+func (s *Scene) ShowStats(show bool) {
+	s.showStats = show
+}
+
+// ShowSafeAreaGuides sets whether Render draws safe area guides over the scene, for composing video that
+// may be cropped. The areas are fractions of the screen size, as with SafeAreaGuides.
+// Pass 0 for both to turn the guides off.
+func (s *Scene) ShowSafeAreaGuides(percentAction, percentTitle float64) {
+	s.safeArea = [2]float64{percentAction, percentTitle}
+}
+
+// Dirty returns whether any object in the scene would be transformed and projected again by rendering it
+// at the given time, because it was marked dirty, it has moved, or the camera has, since the last render.
+// Interactive backends can skip redrawing frames where nothing has changed.
+func (s *Scene) Dirty(t float64) bool {
+	sectors := max(1, s.sectors)
+	for i := range sectors {
+		angle := float64(i) / float64(sectors) * blmath.Tau
+		for _, obj := range s.Objects {
+			if obj.stale(i*2, obj.key(t, angle, false)) || (s.mirror && obj.stale(i*2+1, obj.key(t, angle, true))) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Render draws every object in the scene as it is at the given time.
+// Scenes without keyframes look the same at any time.
+// Each object is kept transformed and projected in a retained draw list, so objects that haven't moved,
+// under a camera that hasn't moved, are drawn again without being reprojected. See SceneObject.MarkDirty.
+func (s *Scene) Render(t float64) {
+	if s.showStats {
+		ResetRenderStats()
+		start := time.Now()
+		defer func() {
+			statsOverlay(GetRenderStats(), time.Since(start)).Stroke(1)
+		}()
+	}
+	sectors := max(1, s.sectors)
+	for i := range sectors {
+		angle := float64(i) / float64(sectors) * blmath.Tau
+		for _, obj := range s.Objects {
+			obj.drawRetained(t, i*2, angle, false)
+			if s.mirror {
+				obj.drawRetained(t, i*2+1, angle, true)
+			}
+		}
+	}
+	for _, obj := range s.Objects {
+		obj.dirty = false
+	}
+	if s.safeArea != [2]float64{} {
+		SafeAreaGuides(s.safeArea[0], s.safeArea[1]).Stroke(1)
+	}
+}
@@ -1,31 +1,210 @@
 // Package wire implements wireframe 3d shapes.
 package wire
 
+import (
+	"math"
+
+	"github.com/bit101/bitlib/blcolor"
+	"github.com/bit101/bitlib/blmath"
+)
+
 // Segment represents a line segment between two points.
 type Segment struct {
 	PointA, PointB *Point
+	// Width multiplies the width this segment is stroked with, so some edges can be heavier than others.
+	// Defaults to 1. A width of 0 is treated as 1, so segments made without NewSegment are drawn normally.
+	Width float64
 }
 
 // NewSegment creates a new segment from two points.
 func NewSegment(a, b *Point) *Segment {
-	return &Segment{a, b}
+	return &Segment{a, b, 1}
+}
+
+// widthScale returns the amount this segment's stroke width is multiplied by.
+func (s *Segment) widthScale() float64 {
+	if s.Width == 0 {
+		return 1
+	}
+	return s.Width
 }
 
 // Stroke draws a line between the two points of this segment.
 func (s *Segment) Stroke(width float64) {
-	world.Context.Save()
-	scale := (s.PointA.Scaling + s.PointB.Scaling) / 2
-	if s.PointA.Visible() && s.PointB.Visible() {
-		ApplyFogAndWaterLevel((s.PointA.Y+s.PointB.Y)/2, (s.PointA.Z+s.PointB.Z)/2)
-		world.Context.SetLineWidth(width * scale)
-		world.Context.MoveTo(s.PointA.Px, s.PointA.Py)
-		world.Context.LineTo(s.PointB.Px, s.PointB.Py)
-		world.Context.Stroke()
+	s.stroke(width, nil)
+}
+
+// StrokeGradient draws a line between the two points of this segment, fading from colorA at PointA to colorB at PointB.
+// The line is drawn in short pieces, each in its own color, so the gradient works with any Context.
+func (s *Segment) StrokeGradient(width float64, colorA, colorB blcolor.Color) {
+	s.strokeGradient(width, &colorA, &colorB)
+}
+
+// stroke draws a line between the two points of this segment, in the given color if it is not nil.
+func (s *Segment) stroke(width float64, color *blcolor.Color) {
+	s.strokeGradient(width, color, color)
+}
+
+// strokeGradient draws a line between the two points of this segment, fading from colorA to colorB.
+// If the colors are nil, the world color is used.
+// Segments that cross the near or far clipping planes are clipped, so only the visible part is drawn.
+// Parts of the segment hidden by occluders (see AddOccluderSphere) are left out.
+// Gradients, long segments if adaptive subdivision is on (see SetAdaptiveSubdivision), and deep segments
+// if depth subdivision is on (see SetDepthSubdivision), are drawn in several pieces.
+func (s *Segment) strokeGradient(width float64, colorA, colorB *blcolor.Color) {
+	a, b, t0, t1, ok := s.clipped()
+	if !ok {
+		renderStats.Culled++
+		return
+	}
+	parts := unoccluded(a, b)
+	if len(parts) == 0 {
+		renderStats.Culled++
+		return
+	}
+	renderStats.Segments++
+	width *= s.widthScale()
+	for _, part := range parts {
+		pa, pb := a, b
+		if part != [2]float64{0, 1} {
+			pa, pb = LerpPoint(part[0], a, b), LerpPoint(part[1], a, b)
+			pa.Project()
+			pb.Project()
+		}
+		strokePieces(pa, pb, blmath.Lerp(part[0], t0, t1), blmath.Lerp(part[1], t0, t1), width, colorA, colorB)
+	}
+}
+
+// strokePieces draws a line between two projected points, which lie between positions t0 and t1 along
+// a segment that fades from colorA to colorB, in as many pieces as are needed for its gradient and subdivision,
+// up to maxStrokePieces. Lines whose projected length is not finite are not drawn.
+func strokePieces(a, b *Point, t0, t1, width float64, colorA, colorB *blcolor.Color) {
+	length := math.Hypot(b.Px-a.Px, b.Py-a.Py)
+	if math.IsNaN(length) || math.IsInf(length, 0) {
+		return
+	}
+	pieces := 1.0
+	if world.MaxStrokeLength > 0 {
+		pieces = math.Max(pieces, math.Ceil(length/world.MaxStrokeLength))
+	}
+	if world.MaxStrokeDepth > 0 {
+		pieces = math.Max(pieces, math.Ceil(math.Abs(b.Z-a.Z)/world.MaxStrokeDepth))
 	}
+	gradient := colorA != nil && colorB != nil && *colorA != *colorB
+	if gradient {
+		pieces = math.Max(pieces, math.Ceil(length/gradientStep))
+	}
+	count := int(math.Min(pieces, maxStrokePieces))
+	p0 := a
+	for i := 1; i <= count; i++ {
+		p1 := b
+		if i < count {
+			p1 = LerpPoint(float64(i)/float64(count), a, b)
+			p1.Project()
+		}
+		color := colorA
+		if gradient {
+			// the color at the middle of this piece, measured along the whole, unclipped segment.
+			t := blmath.Lerp((float64(i)-0.5)/float64(count), t0, t1)
+			c := blcolor.Lerp(*colorA, *colorB, t)
+			color = &c
+		}
+		strokeLine(p0, p1, width, color)
+		p0 = p1
+	}
+}
+
+// gradientStep is the length, in pixels, of each piece of a gradient stroke.
+const gradientStep = 4.0
+
+// maxStrokePieces is the most pieces a single line is drawn in, however long it is on screen.
+const maxStrokePieces = 256
+
+// strokeLine draws a line between two projected points, in the given color if it is not nil.
+// Fog and line width are based on the midpoint of the line.
+func strokeLine(a, b *Point, width float64, color *blcolor.Color) {
+	world.Context.Save()
+	scale := (a.Scaling + b.Scaling) / 2
+	shade(color, (a.Y+b.Y)/2, (a.Z+b.Z)/2, 1)
+	width = snapWidth(projectedSize(width, scale, (a.Z+b.Z)/2))
+	world.Context.SetLineWidth(width)
+	world.Context.MoveTo(snapCoord(a.Px, width), snapCoord(a.Py, width))
+	world.Context.LineTo(snapCoord(b.Px, width), snapCoord(b.Py, width))
+	world.Context.Stroke()
 	world.Context.Restore()
 }
 
+// clipped returns the part of this segment that lies between the near and far clipping planes,
+// with both points projected, and the positions of those points along the segment, from 0 to 1.
+// If both points are visible, they are returned as is. If the segment is entirely outside the planes, ok will be false.
+func (s *Segment) clipped() (*Point, *Point, float64, float64, bool) {
+	a, b := s.PointA, s.PointB
+	if a.Visible() && b.Visible() {
+		return a, b, 0, 1, true
+	}
+	za, zb := a.Z+world.CZ, b.Z+world.CZ
+	t0, t1 := 0.0, 1.0
+	for _, plane := range []struct {
+		z    float64
+		near bool
+	}{{world.NearZ, true}, {world.FarZ, false}} {
+		// distances inside the plane. positive is visible.
+		da, db := za-plane.z, zb-plane.z
+		if !plane.near {
+			da, db = -da, -db
+		}
+		if da < 0 && db < 0 {
+			return nil, nil, 0, 0, false
+		}
+		if da < 0 {
+			t0 = max(t0, da/(da-db))
+		} else if db < 0 {
+			t1 = min(t1, da/(da-db))
+		}
+	}
+	if t0 >= t1 {
+		return nil, nil, 0, 0, false
+	}
+	a = LerpPoint(t0, s.PointA, s.PointB)
+	b = LerpPoint(t1, s.PointA, s.PointB)
+	a.Project()
+	b.Project()
+	return a, b, t0, t1, true
+}
+
+// StrokePartial draws part of this segment, from 0 (nothing) to 1 (the full segment). t is clamped to that range.
+// If fromCenter is true, the line grows outward from the midpoint of the segment towards both ends,
+// otherwise it grows from PointA towards PointB.
+func (s *Segment) StrokePartial(width, t float64, fromCenter bool) {
+	if t <= 0 {
+		return
+	}
+	t = math.Min(t, 1)
+	a, b := s.PointA, s.PointB
+	if fromCenter {
+		a = LerpPoint(0.5-t/2, s.PointA, s.PointB)
+		b = LerpPoint(0.5+t/2, s.PointA, s.PointB)
+	} else {
+		b = LerpPoint(t, s.PointA, s.PointB)
+	}
+	a.Project()
+	b.Project()
+	NewSegment(a, b).Stroke(width * s.widthScale())
+}
+
 // Length returns the length of this segment.
 func (s *Segment) Length() float64 {
 	return s.PointA.Distance(s.PointB)
 }
+
+// ClosestPoint returns a new point on this segment, as close as possible to the given point.
+func (s *Segment) ClosestPoint(p *Point) *Point {
+	a, b := s.PointA, s.PointB
+	dx, dy, dz := b.X-a.X, b.Y-a.Y, b.Z-a.Z
+	lenSq := dx*dx + dy*dy + dz*dz
+	if lenSq == 0 {
+		return a.Clone()
+	}
+	t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy + (p.Z-a.Z)*dz) / lenSq
+	return LerpPoint(blmath.Clamp(t, 0, 1), a, b)
+}
@@ -0,0 +1,61 @@
+package wire
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSegmentClipped(t *testing.T) {
+	saved := world
+	defer func() { world = saved }()
+	SetPerspective(300)
+	SetCenter(0, 0, 500)
+	SetClipping(100, 10000)
+
+	// the near plane is at z = -400 and the camera at z = -500.
+	tests := []struct {
+		name   string
+		za, zb float64
+		ok     bool
+		t0, t1 float64
+	}{
+		{"both in front", 0, 100, true, 0, 1},
+		{"both behind", -600, -700, false, 0, 0},
+		{"one on each side", -600, -200, true, 0.5, 1},
+		{"one on each side reversed", -200, -600, true, 0, 0.5},
+		{"one at the camera", -500, -300, true, 0.5, 1},
+		{"both at the camera", -500, -500, false, 0, 0},
+		{"past the far plane", 0, 19000, true, 0, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSegment(NewPoint(-50, 10, tt.za), NewPoint(50, 10, tt.zb))
+			s.PointA.Project()
+			s.PointB.Project()
+			a, b, t0, t1, ok := s.clipped()
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if math.Abs(t0-tt.t0) > 1e-9 || math.Abs(t1-tt.t1) > 1e-9 {
+				t.Errorf("clipped to %v, %v, want %v, %v", t0, t1, tt.t0, tt.t1)
+			}
+			for _, p := range []*Point{a, b} {
+				if !p.Visible() && math.Abs(p.Z+world.CZ-world.NearZ) > 1e-9 && math.Abs(p.Z+world.CZ-world.FarZ) > 1e-9 {
+					t.Errorf("clipped point at z %v is not between the clipping planes", p.Z)
+				}
+				for _, v := range []float64{p.Px, p.Py} {
+					if math.IsNaN(v) || math.IsInf(v, 0) {
+						t.Fatalf("clipped point projected to %v, %v", p.Px, p.Py)
+					}
+				}
+			}
+			want := LerpPoint(tt.t0, s.PointA, s.PointB)
+			if a.Distance(want) > 1e-9 {
+				t.Errorf("first point %v, want %v", a, want)
+			}
+		})
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"os"
 	"slices"
 	"strconv"
-	"strings"
 )
 
 //////////////////////////////////////////////////////////////
@@ -35,7 +34,9 @@ import (
 // 2 3
 //////////////////////////////////////////////////////////////
 
+// Save saves the shape to a file, with any pending transform applied.
 func (s *Shape) Save(fileName string) {
+	s.ApplyTransform()
 	file, err := os.Create(fileName)
 	checkErr(err)
 	defer file.Close()
@@ -63,81 +64,102 @@ func (s *Shape) Save(fileName string) {
 	}
 }
 
+// LoadShape loads a shape saved with Save. Any problem in the file is returned as an error.
+// Use ImportShape to load as much of a damaged file as possible.
 func LoadShape(fileName string) (*Shape, error) {
+	shape, diag, err := ImportShape(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if diag.HasWarnings() {
+		return nil, errors.New("unable to parse shape: " + diag.Warnings[0].String())
+	}
+	return shape, nil
+}
+
+// ImportShape loads a shape saved with Save, skipping any points or segments that can't be parsed,
+// and segments whose points are missing or out of range, and reporting them in the returned diagnostics.
+// An error is only returned if the file can't be read.
+func ImportShape(fileName string) (*Shape, *Diagnostics, error) {
 	file, err := os.Open(fileName)
 	if err != nil {
-		return nil, errors.New("unable to load shape: " + err.Error())
+		return nil, nil, errors.New("unable to load shape: " + err.Error())
 	}
 	defer file.Close()
 
 	shape := NewShape()
+	diag := newDiagnostics(fileName)
 	scanner := bufio.NewScanner(file)
-
-	// parse points
-	scanner.Scan()
-	numPoints, err := strconv.ParseInt(scanner.Text(), 10, 64)
-	if err != nil {
-		return nil, errors.New("unable to parse shape: " + err.Error())
-	}
-	for range numPoints {
-		scanner.Scan()
-		line := scanner.Text()
-		x, y, z, err := parseCoords(line)
-		if err != nil {
-			return nil, errors.New("unable to parse shape: " + err.Error())
+	lineNum := 0
+	next := func() ([]string, []int, bool) {
+		if !scanner.Scan() {
+			return nil, nil, false
 		}
-		shape.AddXYZ(x, y, z)
+		lineNum++
+		fields, columns := fieldsWithColumns(scanner.Text())
+		return fields, columns, true
 	}
-
-	// parse segments
-	scanner.Scan()
-	numSegments, err := strconv.ParseInt(scanner.Text(), 10, 64)
-	if err != nil {
-		return nil, errors.New("unable to parse shape: " + err.Error())
-	}
-	for range numSegments {
-		scanner.Scan()
-		line := scanner.Text()
-		i, j, err := parseIndices(line)
-		if err != nil {
-			return nil, errors.New("unable to parse shape: " + err.Error())
+	count := func(what string) int {
+		fields, columns, ok := next()
+		if !ok {
+			diag.warn(lineNum+1, 0, "missing %s count", what)
+			return 0
 		}
-		if i < 0 || i >= len(shape.Points) || j < 0 || j >= len(shape.Points) {
-			return nil, errors.New("invalid segment index, should be from zero to length of points minus one")
+		n, ok := diag.parseInts(lineNum, fields, columns, 1)
+		if !ok {
+			return 0
 		}
-		shape.AddSegmentByIndex(i, j)
+		return n[0]
 	}
-	return shape, nil
-}
 
-func parseCoords(line string) (float64, float64, float64, error) {
-	coords := strings.Split(line, " ")
-	x, err := strconv.ParseFloat(coords[0], 64)
-	if err != nil {
-		return 0, 0, 0, err
-	}
-	y, err := strconv.ParseFloat(coords[1], 64)
-	if err != nil {
-		return 0, 0, 0, err
-	}
-	z, err := strconv.ParseFloat(coords[2], 64)
-	if err != nil {
-		return 0, 0, 0, err
+	// points that couldn't be parsed are nil, so the indices of the rest still match the file.
+	points := []*Point{}
+	numPoints := count("point")
+	for i := range numPoints {
+		fields, columns, ok := next()
+		if !ok {
+			diag.warn(lineNum+1, 0, "file ends after %d of %d points", i, numPoints)
+			return shape, diag, nil
+		}
+		coords, ok := diag.parseFloats(lineNum, fields, columns, 3)
+		if !ok {
+			points = append(points, nil)
+			continue
+		}
+		p := NewPoint(coords[0], coords[1], coords[2])
+		shape.AddPoint(p)
+		points = append(points, p)
 	}
-	return x, y, z, nil
-}
 
-func parseIndices(line string) (int, int, error) {
-	indices := strings.Split(line, " ")
-	i, err := strconv.ParseInt(indices[0], 10, 64)
-	if err != nil {
-		return 0, 0, err
+	numSegments := count("segment")
+	for i := range numSegments {
+		fields, columns, ok := next()
+		if !ok {
+			diag.warn(lineNum+1, 0, "file ends after %d of %d segments", i, numSegments)
+			break
+		}
+		indices, ok := diag.parseInts(lineNum, fields, columns, 2)
+		if !ok {
+			continue
+		}
+		valid := true
+		for j, index := range indices {
+			if index < 0 || index >= len(points) {
+				diag.warn(lineNum, columns[j], "point index %d out of range, should be from 0 to %d", index, len(points)-1)
+				valid = false
+			} else if points[index] == nil {
+				diag.warn(lineNum, columns[j], "point %d could not be parsed", index)
+				valid = false
+			}
+		}
+		if valid {
+			shape.AddSegmentByPoints(points[indices[0]], points[indices[1]])
+		}
 	}
-	j, err := strconv.ParseInt(indices[1], 10, 64)
-	if err != nil {
-		return 0, 0, err
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errors.New("unable to load shape: " + err.Error())
 	}
-	return int(i), int(j), nil
+	return shape, diag, nil
 }
 
 func checkErr(err error) {
@@ -2,24 +2,54 @@
 package wire
 
 import (
+	"cmp"
 	"math"
 	"slices"
+	"sync/atomic"
 
+	"github.com/bit101/bitlib/blcolor"
 	"github.com/bit101/bitlib/blmath"
 	"github.com/bit101/bitlib/geom"
 )
 
 // Shape is a 3d shape composed of a list of points and segments connecting them.
 type Shape struct {
-	Points   PointList
-	Segments []*Segment
+	Points    PointList
+	Segments  []*Segment
+	Faces     []*Face
+	Arcs      []*ArcSegment
+	ColorAttr string
+	Palette   Palette
+	// Recipe records how the shape was built, if it was made by a primitive constructor. See Rebuild.
+	Recipe      *Recipe
+	original    PointList
+	frame       PointList
+	progressive []EdgeCollapse
+	advectTime  float64
+	// projected is set on the copies kept by a scene's retained draw list, whose points are already projected.
+	projected bool
+	// id identifies the shape and its clones for boil. See boilID.
+	id uint64
+}
+
+// shapeIDs counts the ids given out by boilID.
+var shapeIDs atomic.Uint64
+
+// boilID returns the shape's id, giving it one the first time, so its points boil the same way wherever it moves.
+func (s *Shape) boilID() uint64 {
+	if s.id == 0 {
+		s.id = shapeIDs.Add(1)
+	}
+	return s.id
 }
 
 // NewShape creates a new shape.
 func NewShape() *Shape {
 	return &Shape{
-		PointList{},
-		[]*Segment{},
+		Points:   PointList{},
+		Segments: []*Segment{},
+		Faces:    []*Face{},
+		Arcs:     []*ArcSegment{},
 	}
 }
 
@@ -60,37 +90,47 @@ func ShapeFromLure(lure Lure3d, count int) *Shape {
 	return s
 }
 
-// AddShape adds the points and segments of another shape to this shape.
+// AddShape adds the points, segments, faces and arcs of another shape to this shape.
 // Does not clone the original shape, so transforms to this shape
-// will affect the added shape as well.
+// will affect the added shape as well. Pending transforms on both shapes are applied first.
 func (s *Shape) AddShape(shape *Shape) {
+	s.ApplyTransform()
+	shape.ApplyTransform()
+	s.Recipe = nil
 	s.Points = append(s.Points, shape.Points...)
 	s.Segments = append(s.Segments, shape.Segments...)
+	s.Faces = append(s.Faces, shape.Faces...)
+	s.Arcs = append(s.Arcs, shape.Arcs...)
 }
 
 // AddPoint adds a point to the shape.
 func (s *Shape) AddPoint(point *Point) {
+	s.Recipe = nil
 	s.Points.Add(point)
 }
 
 // AddXYZ adds a point to the shape.
 func (s *Shape) AddXYZ(x, y, z float64) {
+	s.Recipe = nil
 	s.Points.AddXYZ(x, y, z)
 }
 
 // AddSegment adds a new segment.
 func (s *Shape) AddSegment(seg *Segment) {
+	s.Recipe = nil
 	s.Segments = append(s.Segments, seg)
 }
 
 // AddSegmentByPoints adds a new segment based on the two points passed.
 func (s *Shape) AddSegmentByPoints(a, b *Point) {
+	s.Recipe = nil
 	seg := NewSegment(a, b)
 	s.Segments = append(s.Segments, seg)
 }
 
 // AddSegmentByIndex adds a new segment based on the indexes of the two points passed.
 func (s *Shape) AddSegmentByIndex(a, b int) {
+	s.Recipe = nil
 	seg := NewSegment(s.Points[a], s.Points[b])
 	s.Segments = append(s.Segments, seg)
 }
@@ -143,45 +183,236 @@ func (s *Shape) AddRandomPointInTorus(radius1, radius2, arc float64) {
 
 // GetSize returns the width, depth and height of a shape.
 func (s *Shape) GetSize() (float64, float64, float64) {
+	s.ApplyTransform()
 	return s.Points.GetSize()
 }
 
 // Clone returns a deep copy of this shape.
 func (s *Shape) Clone() *Shape {
 	clone := NewShape()
+	clone.id = s.boilID()
 	clone.Points = s.Points.Clone()
 	for _, seg := range s.Segments {
 		indexA := slices.Index(s.Points, seg.PointA)
 		indexB := slices.Index(s.Points, seg.PointB)
 		clone.AddSegmentByIndex(indexA, indexB)
+		clone.Segments[len(clone.Segments)-1].Width = seg.Width
+	}
+	for _, face := range s.Faces {
+		indices := []int{}
+		for _, p := range face.Points {
+			indices = append(indices, slices.Index(s.Points, p))
+		}
+		clone.AddFaceByIndex(indices...)
+	}
+	for _, arc := range s.Arcs {
+		clone.Arcs = append(clone.Arcs, &ArcSegment{
+			clone.Points[slices.Index(s.Points, arc.Center)],
+			clone.Points[slices.Index(s.Points, arc.PointU)],
+			clone.Points[slices.Index(s.Points, arc.PointV)],
+			arc.Start,
+			arc.End,
+		})
+	}
+	if s.original != nil {
+		clone.original = s.original.Clone()
+	}
+	if s.frame != nil {
+		clone.frame = s.frame.Clone()
 	}
+	clone.ColorAttr = s.ColorAttr
+	clone.Palette = s.Palette
+	clone.Recipe = s.Recipe
+	clone.progressive = s.progressive
+	clone.advectTime = s.advectTime
 	return clone
 }
 
+// Compact moves all of this shape's points into a single contiguous block of memory, in place,
+// and updates its segments, faces and arcs to match. This improves cache locality and reduces garbage
+// collection work for shapes with millions of points, speeding up projection and transforms.
+// It's best done once a large shape is fully built. Points shared with other shapes, such as
+// through AddShape, will no longer be shared afterwards.
+func (s *Shape) Compact() {
+	remap := s.Points.compact()
+	for _, seg := range s.Segments {
+		if p, ok := remap[seg.PointA]; ok {
+			seg.PointA = p
+		}
+		if p, ok := remap[seg.PointB]; ok {
+			seg.PointB = p
+		}
+	}
+	for _, face := range s.Faces {
+		for i, point := range face.Points {
+			if p, ok := remap[point]; ok {
+				face.Points[i] = p
+			}
+		}
+	}
+	for _, arc := range s.Arcs {
+		for _, point := range []**Point{&arc.Center, &arc.PointU, &arc.PointV} {
+			if p, ok := remap[*point]; ok {
+				*point = p
+			}
+		}
+	}
+}
+
 // RemoveSegment removes the given segment from the shape's segment list.
 func (s *Shape) RemoveSegment(seg *Segment) {
 	index := slices.Index(s.Segments, seg)
 	if index > -1 {
+		s.Recipe = nil
 		s.Segments = append(s.Segments[0:index], s.Segments[index+1:]...)
 	}
 }
 
-// Stroke strokes each path in a shape.
+// ColorizeByAttr sets the shape to be colored by mapping the named per-point attribute through a palette
+// when it is stroked or its points are rendered. Attribute values are normalized across the shape's points
+// at render time, so the lowest value maps to the start of the palette and the highest to the end.
+// Points without the attribute take the start of the palette. Segments fade between the colors of their two points.
+// Pass an empty name to go back to drawing in the world color.
+func (s *Shape) ColorizeByAttr(name string, palette Palette) {
+	s.ColorAttr = name
+	s.Palette = palette
+}
+
+// attrPosition returns the normalized position of a point's color attribute within the given range.
+func (s *Shape) attrPosition(p *Point, min, max float64) float64 {
+	value, ok := p.GetAttr(s.ColorAttr)
+	if !ok || max <= min {
+		return 0
+	}
+	return (value - min) / (max - min)
+}
+
+// project applies any deferred transform and projects the shape's points, unless they are already projected.
+func (s *Shape) project() {
+	if s.projected {
+		return
+	}
+	s.ApplyTransform()
+	s.Points.project(s.boilID())
+}
+
+// Stroke strokes each path and arc in a shape.
 func (s *Shape) Stroke(width float64) {
-	s.Points.Project()
-	for _, segment := range s.Segments {
-		segment.Stroke(width)
+	s.project()
+	for _, arc := range s.Arcs {
+		arc.Stroke(width)
+	}
+	if s.ColorAttr == "" {
+		for _, segment := range s.drawOrder() {
+			segment.Stroke(width)
+		}
+		return
+	}
+	min, max := s.Points.AttrRange(s.ColorAttr)
+	for _, segment := range s.drawOrder() {
+		colorA := s.Palette.Color(s.attrPosition(segment.PointA, min, max))
+		colorB := s.Palette.Color(s.attrPosition(segment.PointB, min, max))
+		segment.strokeGradient(width, &colorA, &colorB)
+	}
+}
+
+// StrokeGlow strokes the shape with a glow: first with glowWidth, then blurred by blur, then again with coreWidth
+// over the top, so the lines look lit. The blur applies to everything drawn so far, not just this shape,
+// so glowing shapes are best drawn before anything that should stay sharp. A blur of 0 skips the blur,
+// as does a Context that is not a Blurrer.
+func (s *Shape) StrokeGlow(coreWidth, glowWidth, blur float64) {
+	s.Stroke(glowWidth)
+	if blurrer, ok := world.Context.(Blurrer); ok && blur > 0 {
+		blurrer.GaussianBlur(blur)
 	}
+	s.Stroke(coreWidth)
+}
+
+// StrokeSegmentsPartial strokes part of every segment in the shape at once, from 0 (nothing) to 1 (the full shape),
+// so the whole wireframe grows simultaneously. If fromCenter is true, each segment grows outward from its midpoint,
+// otherwise from its first point to its second.
+func (s *Shape) StrokeSegmentsPartial(width, t float64, fromCenter bool) {
+	s.ApplyTransform()
+	s.Points.project(s.boilID())
+	for _, segment := range s.drawOrder() {
+		segment.StrokePartial(width, t, fromCenter)
+	}
+}
+
+// drawOrder returns the segments of the shape in the order they should be drawn:
+// from back to front if depth sorting is on (see SetDepthSort), otherwise in the order they were added.
+// Segments on the back of the shape are left out if backface culling is on (see SetBackfaceCulling).
+func (s *Shape) drawOrder() []*Segment {
+	segments := s.Segments
+	if world.BackfaceCulling && len(s.Faces) > 0 {
+		segments = s.frontSegments()
+	}
+	if !world.DepthSort {
+		return segments
+	}
+	segments = slices.Clone(segments)
+	slices.SortStableFunc(segments, func(a, b *Segment) int {
+		return cmp.Compare(b.PointA.Z+b.PointB.Z, a.PointA.Z+a.PointB.Z)
+	})
+	return segments
 }
 
 // RenderPoints draws a filled circle for each point in the path.
 func (s *Shape) RenderPoints(radius float64) {
-	s.Points.Project()
-	s.Points.RenderPoints(radius)
+	s.renderPoints(radius, nil)
+}
+
+// RenderPointsByAttr draws a filled circle for each point in the shape, with its radius multiplied by the value
+// of the named per-point attribute, so points can be sized individually, such as stars by their brightness.
+// Points without the attribute are drawn at the given radius.
+func (s *Shape) RenderPointsByAttr(name string, radius float64) {
+	sizes := make([]float64, len(s.Points))
+	for i, p := range s.Points {
+		size, ok := p.GetAttr(name)
+		if !ok {
+			size = 1
+		}
+		sizes[i] = size
+	}
+	s.renderPoints(radius, sizes)
+}
+
+// renderPoints draws a filled circle for each point in the shape, colored by its color attribute if it has one,
+// and sized by sizes if it is not nil.
+func (s *Shape) renderPoints(radius float64, sizes []float64) {
+	s.project()
+	if s.ColorAttr == "" {
+		s.Points.renderPoints(radius, nil, sizes)
+		return
+	}
+	min, max := s.Points.AttrRange(s.ColorAttr)
+	colors := make([]blcolor.Color, len(s.Points))
+	for i, p := range s.Points {
+		colors[i] = s.Palette.Color(s.attrPosition(p, min, max))
+	}
+	s.Points.renderPoints(radius, colors, sizes)
+}
+
+// RenderSoftPoints draws a soft, radial gradient splat for each point in the shape.
+// See PointList.RenderSoftPoints for details on falloff.
+func (s *Shape) RenderSoftPoints(radius, falloff float64) {
+	s.project()
+	s.Points.renderSoftPoints(radius, falloff)
+}
+
+// SetSegmentWidths sets the width of each segment of the shape to the value returned by f for it,
+// as a multiplier on the width the shape is stroked with. This lets structural edges be drawn heavier than detail
+// in the same shape, such as by length, direction or position. Segments made by later modifiers that split segments,
+// such as Subdivide, keep the width of the segment they came from.
+func (s *Shape) SetSegmentWidths(f func(*Segment) float64) {
+	for _, seg := range s.Segments {
+		seg.Width = f(seg)
+	}
 }
 
 // Subdivide subdivides segments so that no segment is longer than maxDist.
 func (s *Shape) Subdivide(maxDist float64) {
+	s.ApplyTransform()
 	newSegs := []*Segment{}
 	for _, seg := range s.Segments {
 		dx := seg.PointB.X - seg.PointA.X
@@ -196,16 +427,18 @@ func (s *Shape) Subdivide(maxDist float64) {
 		for i := 1.0; i < count; i++ {
 			p1 := first.Translated(dx/count*i, dy/count*i, dz/count*i)
 			s.AddPoint(p1)
-			newSegs = append(newSegs, NewSegment(p0, p1))
+			newSegs = append(newSegs, &Segment{p0, p1, seg.Width})
 			p0 = p1
 		}
-		newSegs = append(newSegs, NewSegment(p0, last))
+		newSegs = append(newSegs, &Segment{p0, last, seg.Width})
 	}
 	s.Segments = newSegs
+	s.Recipe = nil
 }
 
 // Cull removes points from the shape that do not satisfy the cull function. Modifies shape in place.
 func (s *Shape) Cull(cullFunc func(*Point) bool) {
+	s.ApplyTransform()
 	segs := []*Segment{}
 	for _, seg := range s.Segments {
 		if cullFunc(seg.PointA) && cullFunc(seg.PointB) {
@@ -214,6 +447,7 @@ func (s *Shape) Cull(cullFunc func(*Point) bool) {
 	}
 	s.Segments = segs
 	s.Points.Cull(cullFunc)
+	s.Recipe = nil
 }
 
 // Culled returns a new shape with points removed that do not satisfy the cull function.
@@ -226,7 +460,9 @@ func (s *Shape) Culled(cullFunc func(*Point) bool) *Shape {
 // CullBox removes points that ar not within the defined box. Modifies the shape in place.
 // TODO: cull segments not just points
 func (s *Shape) CullBox(minX, minY, minZ, maxX, maxY, maxZ float64) {
+	s.ApplyTransform()
 	s.Points.CullBox(minX, minY, minZ, maxX, maxY, maxZ)
+	s.Recipe = nil
 }
 
 // ThinPoints is used to thin out a dense model. It will keep `take` number of points,
@@ -234,6 +470,7 @@ func (s *Shape) CullBox(minX, minY, minZ, maxX, maxY, maxZ float64) {
 // To thin by 25% use ThinPoints(3, 1). To thin by 75%, ThinPoints(1, 3).
 // TODO: remove invalidated segments.
 func (s *Shape) ThinPoints(take, skip int) {
+	s.Recipe = nil
 	points := NewPointList()
 	i := 0
 	max := len(s.Points)
@@ -261,6 +498,7 @@ func (s *Shape) ThinPoints(take, skip int) {
 // then adds those points to a new shape and returns that.
 // TODO: handle segments
 func (s *Shape) Split(split func(p *Point) bool) *Shape {
+	s.ApplyTransform()
 	newShape := s.Culled(split)
 	s.Cull(func(p *Point) bool {
 		return !split(p)
@@ -270,6 +508,7 @@ func (s *Shape) Split(split func(p *Point) bool) *Shape {
 
 // Center centers the shape on all axes.
 func (s *Shape) Center() {
+	s.ApplyTransform()
 	s.Points.Center()
 }
 
@@ -277,6 +516,7 @@ func (s *Shape) Center() {
 // along the z-axis. The shape will retain its relative width, measured along the curve.
 // The radius of the cylindar will be dynamically computed.
 func (s *Shape) WrapCylinderWithArc(arc float64) {
+	s.ApplyTransform()
 	s.Points.WrapCylinderWithArc(arc)
 }
 
@@ -284,6 +524,7 @@ func (s *Shape) WrapCylinderWithArc(arc float64) {
 // along the z-axis. The shape will retain its relative width, measured along the curve.
 // The resulting arc the shape covers will be dynamically computed.
 func (s *Shape) WrapCylinderWithRadius(radius float64) {
+	s.ApplyTransform()
 	s.Points.WrapCylinderWithRadius(radius)
 }
 
@@ -291,113 +532,374 @@ func (s *Shape) WrapCylinderWithRadius(radius float64) {
 // laying along the z-axis. The shape will be stretched or compressed to fit in the given
 // arc and radius.
 func (s *Shape) WrapCylinderWithRadiusAndArc(radius, arc float64) {
+	s.ApplyTransform()
 	s.Points.WrapCylinderWithRadiusAndArc(radius, arc)
 }
 
+// WrapSphere wraps a shape lying on the xz plane around an imaginary sphere.
+// See PointList.WrapSphere.
+func (s *Shape) WrapSphere(radius, arcX, arcY, t float64) {
+	s.ApplyTransform()
+	s.Points.WrapSphere(radius, arcX, arcY, t)
+}
+
+// WrapTorus wraps a shape lying on the xy plane around an imaginary torus.
+// See PointList.WrapTorus.
+func (s *Shape) WrapTorus(r1, r2, arc, t float64) {
+	s.ApplyTransform()
+	s.Points.WrapTorus(r1, r2, arc, t)
+}
+
+// Spiralize coils a shape laid out along the x-axis into an Archimedean spiral.
+// See PointList.Spiralize.
+func (s *Shape) Spiralize(pitch, radius, t float64) {
+	s.ApplyTransform()
+	s.Points.Spiralize(pitch, radius, t)
+}
+
 // TwistX twists the shape around the x axis.
 func (s *Shape) TwistX(amt float64) {
+	s.ApplyTransform()
 	s.Points.TwistX(amt)
 }
 
 // TwistY twists the shape around the y axis.
 func (s *Shape) TwistY(amt float64) {
+	s.ApplyTransform()
 	s.Points.TwistY(amt)
 }
 
 // TwistZ twists the shape around the z axis.
 func (s *Shape) TwistZ(amt float64) {
+	s.ApplyTransform()
 	s.Points.TwistZ(amt)
 }
 
+// TwistAxis twists the shape around an arbitrary axis, with an optional falloff along it.
+// See PointList.TwistAxis.
+func (s *Shape) TwistAxis(axis *Point, amt float64, falloff func(t float64) float64) {
+	s.ApplyTransform()
+	s.Points.TwistAxis(axis, amt, falloff)
+}
+
+// Taper scales the cross-sections of the shape progressively along the given axis.
+// See PointList.Taper.
+func (s *Shape) Taper(axis Axis, amount float64) {
+	s.ApplyTransform()
+	s.Points.Taper(axis, amount)
+}
+
+// TaperWithCurve tapers the shape along the given axis, following the given easing curve.
+// See PointList.TaperWithCurve.
+func (s *Shape) TaperWithCurve(axis Axis, amount float64, curve func(t, start, end float64) float64) {
+	s.ApplyTransform()
+	s.Points.TaperWithCurve(axis, amount, curve)
+}
+
+// Spherify moves each point of the shape towards its position on the shape's bounding sphere.
+// See PointList.Spherify.
+func (s *Shape) Spherify(t float64) {
+	s.ApplyTransform()
+	s.Points.Spherify(t)
+}
+
+// ProjectToSphere moves each point of the shape onto a sphere centered on the origin, keeping its segments.
+// See PointList.ProjectToSphere.
+func (s *Shape) ProjectToSphere(radius float64) {
+	s.ApplyTransform()
+	s.Points.ProjectToSphere(radius)
+}
+
+// ProjectToCylinder moves each point of the shape onto a cylinder along the y-axis, keeping its segments.
+// See PointList.ProjectToCylinder.
+func (s *Shape) ProjectToCylinder(radius float64) {
+	s.ApplyTransform()
+	s.Points.ProjectToCylinder(radius)
+}
+
+// ProjectToPlane moves each point of the shape onto a plane, keeping its segments.
+// See PointList.ProjectToPlane.
+func (s *Shape) ProjectToPlane(planePoint, planeNormal *Point) {
+	s.ApplyTransform()
+	s.Points.ProjectToPlane(planePoint, planeNormal)
+}
+
+// Smooth relaxes the shape by moving each point towards the average position of the points it shares
+// segments with, from 0 (unchanged) to 1 (fully to the average), repeated for the given number of iterations.
+// Smooths out noise and jagged detail, while shrinking the shape slightly with each iteration.
+// Points without segments are not moved.
+func (s *Shape) Smooth(iterations int, strength float64) {
+	s.ApplyTransform()
+	n := len(s.Points)
+	indices := map[*Point]int{}
+	for i, p := range s.Points {
+		indices[p] = i
+	}
+	sums := make([]Point, n)
+	counts := make([]int, n)
+	for range iterations {
+		clear(sums)
+		clear(counts)
+		for _, seg := range s.Segments {
+			i, okA := indices[seg.PointA]
+			j, okB := indices[seg.PointB]
+			if !okA || !okB {
+				continue
+			}
+			sums[i].X += seg.PointB.X
+			sums[i].Y += seg.PointB.Y
+			sums[i].Z += seg.PointB.Z
+			sums[j].X += seg.PointA.X
+			sums[j].Y += seg.PointA.Y
+			sums[j].Z += seg.PointA.Z
+			counts[i]++
+			counts[j]++
+		}
+		// every average is found before any point moves, so the result doesn't depend on the order of the points.
+		for i, p := range s.Points {
+			if counts[i] == 0 {
+				continue
+			}
+			c := float64(counts[i])
+			p.Lerp(strength, NewPoint(sums[i].X/c, sums[i].Y/c, sums[i].Z/c))
+		}
+	}
+}
+
+// Shrinkwrap moves each point of the shape toward the nearest place on the target shape, from 0 (unchanged)
+// to 1 (fully on the target), so the shape melts onto or conforms to the target. If the target has segments,
+// points are pulled onto the nearest point along any of them, otherwise onto its nearest point.
+// Every point is checked against the whole target, so this can be slow with large shapes.
+func (s *Shape) Shrinkwrap(target *Shape, amount float64) {
+	s.ApplyTransform()
+	target.ApplyTransform()
+	for _, p := range s.Points {
+		var nearest *Point
+		best := math.MaxFloat64
+		if len(target.Segments) > 0 {
+			for _, seg := range target.Segments {
+				c := seg.ClosestPoint(p)
+				if d := c.Distance(p); d < best {
+					best, nearest = d, c
+				}
+			}
+		} else {
+			for _, t := range target.Points {
+				if d := t.Distance(p); d < best {
+					best, nearest = d, t
+				}
+			}
+		}
+		if nearest != nil {
+			p.Lerp(amount, nearest)
+		}
+	}
+}
+
+// Wave displaces the shape with a sine wave traveling along the given axis.
+// See PointList.Wave.
+func (s *Shape) Wave(axis Axis, amplitude, wavelength, phase float64) {
+	s.ApplyTransform()
+	s.Points.Wave(axis, amplitude, wavelength, phase)
+}
+
+// Ripple displaces the shape with circular waves spreading out from center.
+// See PointList.Ripple.
+func (s *Shape) Ripple(center *Point, amplitude, wavelength, phase float64) {
+	s.ApplyTransform()
+	s.Points.Ripple(center, amplitude, wavelength, phase)
+}
+
+// Shell returns a copy of this shape joined to a second copy moved offset units along the vertex normals,
+// or away from the center for points without faces. A negative offset moves the copy inward.
+func (s *Shape) Shell(offset float64) *Shape {
+	shell := s.Clone()
+	shell.ReleaseOriginal()
+	outer := shell.Clone()
+	normals := outer.vertexNormals()
+	cx, cy, cz := outer.Points.centroid()
+	for _, p := range outer.Points {
+		n, ok := normals[p]
+		if !ok {
+			n = NewPoint(p.X-cx, p.Y-cy, p.Z-cz)
+			if n.Magnitude() == 0 {
+				continue
+			}
+			n.Normalize()
+		}
+		p.Translate(n.X*offset, n.Y*offset, n.Z*offset)
+	}
+	count := len(shell.Points)
+	shell.AddShape(outer)
+	for i := range count {
+		shell.AddSegmentByIndex(i, count+i)
+	}
+	return shell
+}
+
 //////////////////////////////
 // Transform in place.
 //////////////////////////////
 
 // TranslateX translates this shape on the x-axis, in place.
 func (s *Shape) TranslateX(tx float64) {
-	s.Points.TranslateX(tx)
+	s.transformTarget().TranslateX(tx)
 }
 
 // TranslateY translates this shape on the y-axis, in place.
 func (s *Shape) TranslateY(ty float64) {
-	s.Points.TranslateY(ty)
+	s.transformTarget().TranslateY(ty)
 }
 
 // TranslateZ translates this shape on the z-axis, in place.
 func (s *Shape) TranslateZ(tz float64) {
-	s.Points.TranslateZ(tz)
+	s.transformTarget().TranslateZ(tz)
 }
 
 // Translate translates this shape on all axes, in place.
 func (s *Shape) Translate(tx, ty, tz float64) {
-	s.Points.Translate(tx, ty, tz)
+	s.transformTarget().Translate(tx, ty, tz)
 }
 
 // RotateX rotates this shape around the x-axis, in place.
 func (s *Shape) RotateX(angle float64) {
-	s.Points.RotateX(angle)
+	s.transformTarget().RotateX(angle)
 }
 
 // RotateY rotates this shape around the y-axis, in place.
 func (s *Shape) RotateY(angle float64) {
-	s.Points.RotateY(angle)
+	s.transformTarget().RotateY(angle)
 }
 
 // RotateZ rotates this shape around the z-axis, in place.
 func (s *Shape) RotateZ(angle float64) {
-	s.Points.RotateZ(angle)
+	s.transformTarget().RotateZ(angle)
 }
 
 // Rotate rotates this shape around all axes, in place.
 func (s *Shape) Rotate(rx, ry, rz float64) {
-	s.Points.Rotate(rx, ry, rz)
+	s.transformTarget().Rotate(rx, ry, rz)
+}
+
+// RotateAxis rotates this shape around an arbitrary axis through the origin, in place.
+// See Point.RotateAxis.
+func (s *Shape) RotateAxis(axis *Point, angle float64) {
+	s.transformTarget().RotateAxis(axis, angle)
+}
+
+// RotateAround rotates this shape around all axes, centered on the given pivot, in place.
+func (s *Shape) RotateAround(pivot *Point, rx, ry, rz float64) {
+	s.transformTarget().RotateAround(pivot, rx, ry, rz)
 }
 
 // ScaleX scales this shape on the x-axis, in place.
 func (s *Shape) ScaleX(scale float64) {
-	s.Points.ScaleX(scale)
+	s.transformTarget().ScaleX(scale)
 }
 
 // ScaleY scales this shape on the y-axis, in place.
 func (s *Shape) ScaleY(scale float64) {
-	s.Points.ScaleY(scale)
+	s.transformTarget().ScaleY(scale)
 }
 
 // ScaleZ scales this shape on the z-axis, in place.
 func (s *Shape) ScaleZ(scale float64) {
-	s.Points.ScaleZ(scale)
+	s.transformTarget().ScaleZ(scale)
 }
 
 // Scale scales this shape on all axes, in place.
 func (s *Shape) Scale(sx, sy, sz float64) {
-	s.Points.Scale(sx, sy, sz)
+	s.transformTarget().Scale(sx, sy, sz)
 }
 
 // UniScale scales this shape by the same amount on each axis, in place.
 func (s *Shape) UniScale(scale float64) {
-	s.Points.UniScale(scale)
+	s.transformTarget().UniScale(scale)
+}
+
+// Stretch scales this shape by amount along the given axis, and inversely on the other two axes, in place.
+// The volume stays the same, giving the squash and stretch of character animation: amounts above 1 stretch
+// the shape long and thin, and below 1 squash it short and wide. Like Scale, it is centered on the origin.
+func (s *Shape) Stretch(axis Axis, amount float64) {
+	s.Scale(stretchScales(axis, amount))
+}
+
+// ScaleAround scales this shape by the same amount on each axis, centered on the given pivot, in place.
+func (s *Shape) ScaleAround(pivot *Point, scale float64) {
+	s.transformTarget().ScaleAround(pivot, scale)
+}
+
+// ShearXY shears this shape on the xy plane, in place.
+func (s *Shape) ShearXY(amount float64) {
+	s.transformTarget().ShearXY(amount)
+}
+
+// ShearXZ shears this shape on the xz plane, in place.
+func (s *Shape) ShearXZ(amount float64) {
+	s.transformTarget().ShearXZ(amount)
+}
+
+// ShearYZ shears this shape on the yz plane, in place.
+func (s *Shape) ShearYZ(amount float64) {
+	s.transformTarget().ShearYZ(amount)
+}
+
+// Shear shears this shape on all three planes, in place.
+func (s *Shape) Shear(xy, xz, yz float64) {
+	s.transformTarget().Shear(xy, xz, yz)
 }
 
 // RandomizeX randomizes this shape on the x-axis, in place.
 func (s *Shape) RandomizeX(amount float64) {
+	s.ApplyTransform()
 	s.Points.RandomizeX(amount)
 }
 
 // RandomizeY randomizes this shape on the y-axis, in place.
 func (s *Shape) RandomizeY(amount float64) {
+	s.ApplyTransform()
 	s.Points.RandomizeY(amount)
 }
 
 // RandomizeZ randmizes this shape on the z-axis, in place.
 func (s *Shape) RandomizeZ(amount float64) {
+	s.ApplyTransform()
 	s.Points.RandomizeZ(amount)
 }
 
 // Randomize randomizes this shape on all axes, in place.
 func (s *Shape) Randomize(amount float64) {
+	s.ApplyTransform()
 	s.Points.Randomize(amount)
 }
 
+// RandomizeAxes randomizes this shape by a separate amount on each axis, using the given distribution, in place.
+func (s *Shape) RandomizeAxes(ax, ay, az float64, dist RandomDistribution) {
+	s.ApplyTransform()
+	s.Points.RandomizeAxes(ax, ay, az, dist)
+}
+
+// RandomizeRadial randomizes this shape toward or away from its centroid only, in place.
+// See PointList.RandomizeRadial.
+func (s *Shape) RandomizeRadial(amount float64) {
+	s.ApplyTransform()
+	s.Points.RandomizeRadial(amount)
+}
+
+// RandomizeTangential randomizes this shape across its direction from its centroid only, in place.
+// See PointList.RandomizeTangential.
+func (s *Shape) RandomizeTangential(amount float64) {
+	s.ApplyTransform()
+	s.Points.RandomizeTangential(amount)
+}
+
+// RandomizeLooped moves each point of this shape by a smoothly wandering random offset that repeats every period.
+// See PointList.RandomizeLooped.
+func (s *Shape) RandomizeLooped(amount, t, period float64) {
+	s.ApplyTransform()
+	s.Points.RandomizeLooped(amount, t, period)
+}
+
 //////////////////////////////
 // Transform and return new
 //////////////////////////////
@@ -458,6 +960,20 @@ func (s *Shape) Rotated(rx, ry, rz float64) *Shape {
 	return s1
 }
 
+// RotatedAxis returns a copy of this shape, rotated around an arbitrary axis through the origin.
+func (s *Shape) RotatedAxis(axis *Point, angle float64) *Shape {
+	s1 := s.Clone()
+	s1.RotateAxis(axis, angle)
+	return s1
+}
+
+// RotatedAround returns a copy of this shape, rotated on all axes around the given pivot.
+func (s *Shape) RotatedAround(pivot *Point, rx, ry, rz float64) *Shape {
+	s1 := s.Clone()
+	s1.RotateAround(pivot, rx, ry, rz)
+	return s1
+}
+
 // ScaledX returns a copy of this shape, scaled on the x-axis.
 func (s *Shape) ScaledX(scale float64) *Shape {
 	s1 := s.Clone()
@@ -493,6 +1009,48 @@ func (s *Shape) UniScaled(scale float64) *Shape {
 	return s1
 }
 
+// Stretched returns a copy of this shape, stretched along the given axis, preserving its volume.
+func (s *Shape) Stretched(axis Axis, amount float64) *Shape {
+	s1 := s.Clone()
+	s1.Stretch(axis, amount)
+	return s1
+}
+
+// ScaledAround returns a copy of this shape, scaled by the same amount on each axis around the given pivot.
+func (s *Shape) ScaledAround(pivot *Point, scale float64) *Shape {
+	s1 := s.Clone()
+	s1.ScaleAround(pivot, scale)
+	return s1
+}
+
+// ShearedXY returns a copy of this shape, sheared on the xy plane.
+func (s *Shape) ShearedXY(amount float64) *Shape {
+	s1 := s.Clone()
+	s1.ShearXY(amount)
+	return s1
+}
+
+// ShearedXZ returns a copy of this shape, sheared on the xz plane.
+func (s *Shape) ShearedXZ(amount float64) *Shape {
+	s1 := s.Clone()
+	s1.ShearXZ(amount)
+	return s1
+}
+
+// ShearedYZ returns a copy of this shape, sheared on the yz plane.
+func (s *Shape) ShearedYZ(amount float64) *Shape {
+	s1 := s.Clone()
+	s1.ShearYZ(amount)
+	return s1
+}
+
+// Sheared returns a copy of this shape, sheared on all three planes.
+func (s *Shape) Sheared(xy, xz, yz float64) *Shape {
+	s1 := s.Clone()
+	s1.Shear(xy, xz, yz)
+	return s1
+}
+
 // RandomizedX returns a copy of this shape, randomized on the x-axis.
 func (s *Shape) RandomizedX(amount float64) *Shape {
 	s1 := s.Clone()
@@ -521,9 +1079,39 @@ func (s *Shape) Randomized(amount float64) *Shape {
 	return s1
 }
 
+// RandomizedAxes returns a copy of this shape, randomized by a separate amount on each axis, using the given distribution.
+func (s *Shape) RandomizedAxes(ax, ay, az float64, dist RandomDistribution) *Shape {
+	s1 := s.Clone()
+	s1.RandomizeAxes(ax, ay, az, dist)
+	return s1
+}
+
+// RandomizedRadial returns a copy of this shape, randomized toward or away from its centroid only.
+func (s *Shape) RandomizedRadial(amount float64) *Shape {
+	s1 := s.Clone()
+	s1.RandomizeRadial(amount)
+	return s1
+}
+
+// RandomizedTangential returns a copy of this shape, randomized across its direction from its centroid only.
+func (s *Shape) RandomizedTangential(amount float64) *Shape {
+	s1 := s.Clone()
+	s1.RandomizeTangential(amount)
+	return s1
+}
+
+// RandomizedLooped returns a copy of this shape, moved by a smoothly wandering random offset that repeats every period.
+// See PointList.RandomizeLooped.
+func (s *Shape) RandomizedLooped(amount, t, period float64) *Shape {
+	s1 := s.Clone()
+	s1.RandomizeLooped(amount, t, period)
+	return s1
+}
+
 // ConvexHull3d returns a new shape consisting of a stack of convex hulls oriented along the y axis.
 // dy determines the distance on the y access between each slice.
 func (s *Shape) ConvexHull3d(dy float64) *Shape {
+	s.ApplyTransform()
 	newShape := NewShape()
 	shape := s.Clone()
 	shape.Center()
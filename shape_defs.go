@@ -2,9 +2,14 @@
 package wire
 
 import (
+	"cmp"
+	"fmt"
 	"math"
+	"math/rand"
+	"slices"
 
 	"github.com/bit101/bitlib/blmath"
+	"github.com/bit101/bitlib/noise"
 )
 
 // Box creates a 3d box shape.
@@ -34,6 +39,7 @@ func Box(w, h, d float64) *Shape {
 	shape.AddSegmentByIndex(2, 6)
 	shape.AddSegmentByIndex(3, 7)
 	shape.Scale(w/2, h/2, d/2)
+	shape.Recipe = &Recipe{"box", map[string]float64{"w": w, "h": h, "d": d}}
 	return shape
 }
 
@@ -60,6 +66,7 @@ func Circle(radius float64, res int) *Shape {
 	p, s := CirclePath(radius, res)
 	shape.Points = p
 	shape.Segments = s
+	shape.Recipe = &Recipe{"circle", map[string]float64{"radius": radius, "res": float64(res)}}
 	return shape
 }
 
@@ -85,12 +92,21 @@ func Cone(height, radius0, radius1 float64, slices, res int, showSlices, showLon
 			}
 		}
 	}
+	shape.Recipe = &Recipe{"cone", map[string]float64{
+		"height": height, "radius0": radius0, "radius1": radius1,
+		"slices": float64(slices), "res": float64(res), "showSlices": boolParam(showSlices), "showLong": boolParam(showLong),
+	}}
 	return shape
 }
 
 // Cylinder creates a 3d cylinder shape made of a number of circular slices.
 func Cylinder(height, radius float64, slices, res int, showSlices, showLong bool) *Shape {
-	return Cone(height, radius, radius, slices, res, showSlices, showLong)
+	shape := Cone(height, radius, radius, slices, res, showSlices, showLong)
+	shape.Recipe = &Recipe{"cylinder", map[string]float64{
+		"height": height, "radius": radius,
+		"slices": float64(slices), "res": float64(res), "showSlices": boolParam(showSlices), "showLong": boolParam(showLong),
+	}}
+	return shape
 }
 
 // GridBox creates a 3d box shape where each surface is a grid.
@@ -114,20 +130,14 @@ func GridBox(w, h, d float64, xCount, yCount, zCount int, inner bool) *Shape {
 	}
 
 	// segments - it's O(N^2), but deliciously simple, works for inner and outer.
-	for i := 0; i < len(shape.Points)-1; i++ {
-		for j := i + 1; j < len(shape.Points); j++ {
-			a := shape.Points[i]
-			b := shape.Points[j]
-			// adjacent, connected points will be exactly 1 unit apart.
-			// non-adjacent will be at least Sqrt2 apart (diagonal on the same plane)
-			if a.Distance(b) < math.Sqrt2 {
-				shape.AddSegmentByPoints(a, b)
-			}
-		}
-	}
+	connectGrid(shape)
 	shape.Scale(w/fx, h/fy, d/fz)
 	shape.Translate(-w/2, -h/2, -d/2)
 
+	shape.Recipe = &Recipe{"gridbox", map[string]float64{
+		"w": w, "h": h, "d": d,
+		"xCount": float64(xCount), "yCount": float64(yCount), "zCount": float64(zCount), "inner": boolParam(inner),
+	}}
 	return shape
 }
 
@@ -144,26 +154,40 @@ func GridPlane(w, d float64, rows, cols int) *Shape {
 	}
 
 	// segments - it's O(N^2), but deliciously simple, works for inner and outer.
-	for i := 0; i < len(shape.Points)-1; i++ {
-		for j := i + 1; j < len(shape.Points); j++ {
-			a := shape.Points[i]
-			b := shape.Points[j]
+	connectGrid(shape)
+	shape.Scale(w/fx, 1, d/fz)
+	shape.Translate(-w/2, 0, -d/2)
+
+	shape.Recipe = &Recipe{"gridplane", map[string]float64{"w": w, "d": d, "rows": float64(rows), "cols": float64(cols)}}
+	return shape
+}
+
+// connectGrid connects all the points in a shape made of a unit grid to their direct neighbors.
+// Rows of the comparison are split across the world's workers, and the segments are added in order.
+func connectGrid(shape *Shape) {
+	points := shape.Points
+	rows := make([][]int, len(points))
+	parallelFor(len(points), func(i int) {
+		for j := i + 1; j < len(points); j++ {
 			// adjacent, connected points will be exactly 1 unit apart.
 			// non-adjacent will be at least Sqrt2 apart (diagonal on the same plane)
-			if a.Distance(b) < math.Sqrt2 {
-				shape.AddSegmentByPoints(a, b)
+			if points[i].Distance(points[j]) < math.Sqrt2 {
+				rows[i] = append(rows[i], j)
 			}
 		}
+	})
+	for i, row := range rows {
+		for _, j := range row {
+			shape.AddSegmentByIndex(i, j)
+		}
 	}
-	shape.Scale(w/fx, 1, d/fz)
-	shape.Translate(-w/2, 0, -d/2)
-
-	return shape
 }
 
 // Pyramid creates a 3d pyramid shape.
 func Pyramid(height, baseRadius float64, sides int) *Shape {
-	return Cone(height, 0, baseRadius, 2, sides, true, true)
+	shape := Cone(height, 0, baseRadius, 2, sides, true, true)
+	shape.Recipe = &Recipe{"pyramid", map[string]float64{"height": height, "baseRadius": baseRadius, "sides": float64(sides)}}
+	return shape
 }
 
 // RandomInnerBox creates a 3d box filled with random points.
@@ -259,6 +283,25 @@ func Sphere(radius float64, long, lat int, showLong, showLat bool) *Shape {
 		}
 	}
 	shape.UniScale(radius)
+	shape.Recipe = &Recipe{"sphere", map[string]float64{
+		"radius": radius, "long": float64(long), "lat": float64(lat),
+		"showLong": boolParam(showLong), "showLat": boolParam(showLat),
+	}}
+	return shape
+}
+
+// FibonacciSphere creates a 3d sphere of evenly spaced points on the surface of the sphere,
+// arranged in a sunflower (golden angle) spiral from pole to pole.
+func FibonacciSphere(radius float64, count int) *Shape {
+	shape := NewShape()
+	angle := math.Pi * (3 - math.Sqrt(5))
+	for i := range count {
+		y := 1 - (float64(i)+0.5)/float64(count)*2
+		r := math.Sqrt(1 - y*y)
+		t := angle * float64(i)
+		shape.AddXYZ(math.Cos(t)*r, y, math.Sin(t)*r)
+	}
+	shape.UniScale(radius)
 	return shape
 }
 
@@ -277,6 +320,7 @@ func Spring(height, r0, r1, turns, res float64) *Shape {
 	for i := range len(shape.Points) - 1 {
 		shape.AddSegmentByIndex(i, i+1)
 	}
+	shape.Recipe = &Recipe{"spring", map[string]float64{"height": height, "r0": r0, "r1": r1, "turns": turns, "res": res}}
 	return shape
 }
 
@@ -312,6 +356,10 @@ func Torus(r1, r2, arc float64, slices, res int, showSlices, showLong bool) *Sha
 			}
 		}
 	}
+	shape.Recipe = &Recipe{"torus", map[string]float64{
+		"r1": r1, "r2": r2, "arc": arc,
+		"slices": float64(slices), "res": float64(res), "showSlices": boolParam(showSlices), "showLong": boolParam(showLong),
+	}}
 	return shape
 }
 
@@ -332,6 +380,90 @@ func TorusKnot(p, q, r1, r2, res float64) *Shape {
 	for i := 0; i < len(shape.Points); i++ {
 		shape.AddSegmentByIndex(i, (i+1)%len(shape.Points))
 	}
+	shape.Recipe = &Recipe{"torusknot", map[string]float64{"p": p, "q": q, "r1": r1, "r2": r2, "res": res}}
+	return shape
+}
+
+// FrenetRibbon creates a flat ribbon of the given width that follows a parametric curve, twisting as the curve does.
+// The curve is sampled res times for t from 0 to 1. At each sample, the ribbon spans the curve's Frenet normal,
+// and is made of two rails with a rung across between them. If the curve ends where it starts,
+// the ribbon is closed into a loop, joining with a half twist if the normal has turned over along the way.
+// On straight stretches, where the curve has no normal, the last normal is carried forward,
+// and the ribbon is kept from flipping over where the curve changes the direction it bends.
+func FrenetRibbon(curve func(t float64) (x, y, z float64), width float64, res int) *Shape {
+	shape := NewShape()
+	h := 1e-4
+	tangent := func(t float64) (float64, float64, float64) {
+		x0, y0, z0 := curve(t - h)
+		x1, y1, z1 := curve(t + h)
+		dx, dy, dz := x1-x0, y1-y0, z1-z0
+		mag := math.Sqrt(dx*dx + dy*dy + dz*dz)
+		if mag == 0 {
+			return 0, 0, 0
+		}
+		return dx / mag, dy / mag, dz / mag
+	}
+
+	x0, y0, z0 := curve(0)
+	x1, y1, z1 := curve(1)
+	closed := math.Abs(x1-x0)+math.Abs(y1-y0)+math.Abs(z1-z0) < 1e-9
+	count := res + 1
+	if closed {
+		count = res
+	}
+
+	// previous normal. starts as an arbitrary direction, used if the curve starts out straight.
+	px, py, pz := 0.0, 1.0, 0.0
+	for i := range count {
+		t := float64(i) / float64(res)
+		tx, ty, tz := tangent(t)
+		ax, ay, az := tangent(t - h)
+		bx, by, bz := tangent(t + h)
+		nx, ny, nz := bx-ax, by-ay, bz-az
+		if math.Sqrt(nx*nx+ny*ny+nz*nz) < 1e-9 {
+			nx, ny, nz = px, py, pz
+		}
+		// keep only the part of the normal perpendicular to the tangent.
+		d := nx*tx + ny*ty + nz*tz
+		nx, ny, nz = nx-tx*d, ny-ty*d, nz-tz*d
+		mag := math.Sqrt(nx*nx + ny*ny + nz*nz)
+		if mag < 1e-9 {
+			// the fallback was parallel to the tangent. any perpendicular will do.
+			nx, ny, nz = ty, -tx, 0
+			if math.Abs(tz) > 0.9 {
+				nx, ny, nz = 0, tz, -ty
+			}
+			mag = math.Sqrt(nx*nx + ny*ny + nz*nz)
+		}
+		nx, ny, nz = nx/mag*width/2, ny/mag*width/2, nz/mag*width/2
+		// the Frenet normal reverses at inflection points. keep the ribbon from flipping over there.
+		if i > 0 && nx*px+ny*py+nz*pz < 0 {
+			nx, ny, nz = -nx, -ny, -nz
+		}
+		px, py, pz = nx, ny, nz
+
+		x, y, z := curve(t)
+		shape.AddXYZ(x+nx, y+ny, z+nz)
+		shape.AddXYZ(x-nx, y-ny, z-nz)
+		shape.AddSegmentByIndex(i*2, i*2+1)
+		if i > 0 {
+			shape.AddSegmentByIndex(i*2-2, i*2)
+			shape.AddSegmentByIndex(i*2-1, i*2+1)
+		}
+	}
+	if closed && count > 1 {
+		last := (count - 1) * 2
+		a, b := shape.Points[last], shape.Points[0]
+		c := shape.Points[1]
+		// join each rail to whichever rail it is nearest at the start.
+		if a.Distance(b) <= a.Distance(c) {
+			shape.AddSegmentByIndex(last, 0)
+			shape.AddSegmentByIndex(last+1, 1)
+		} else {
+			shape.AddSegmentByIndex(last, 1)
+			shape.AddSegmentByIndex(last+1, 0)
+		}
+	}
 	return shape
 }
 
@@ -355,12 +487,15 @@ func Tetrahedron(size float64) *Shape {
 	model.RotateX(-math.Pi / 2)
 
 	model.UniScale(size)
+	model.Recipe = &Recipe{"tetrahedron", map[string]float64{"size": size}}
 	return model
 }
 
 // Cube creates a cube shape.
 func Cube(size float64) *Shape {
-	return Box(size, size, size)
+	shape := Box(size, size, size)
+	shape.Recipe = &Recipe{"cube", map[string]float64{"size": size}}
+	return shape
 }
 
 // Octahedron creates an octahedron shape.
@@ -386,6 +521,7 @@ func Octahedron(size float64) *Shape {
 	model.AddSegmentByIndex(4, 5)
 
 	model.UniScale(size)
+	model.Recipe = &Recipe{"octahedron", map[string]float64{"size": size}}
 	return model
 }
 
@@ -457,6 +593,7 @@ func Dodecahedron(size float64) *Shape {
 	model.AddSegmentByIndex(18, 19)
 
 	model.UniScale(size)
+	model.Recipe = &Recipe{"dodecahedron", map[string]float64{"size": size}}
 	return model
 }
 
@@ -519,5 +656,474 @@ func Icosahedron(size float64) *Shape {
 	model.AddSegmentByIndex(10, 11)
 
 	model.UniScale(size)
+	model.Recipe = &Recipe{"icosahedron", map[string]float64{"size": size}}
 	return model
 }
+
+// Icosphere creates a sphere from an icosahedron whose triangles have been repeatedly subdivided into four,
+// with the new points pushed out to the surface of the sphere. This gives very even triangles over the whole sphere.
+// Points are shared between neighboring triangles, and each triangle is added as a face.
+func Icosphere(radius float64, subdivisions int) *Shape {
+	ico := Icosahedron(1)
+	ico.Points.Normalize()
+	points := ico.Points
+
+	// find the icosahedron's triangles from its edges.
+	indices := map[*Point]int{}
+	for i, p := range points {
+		indices[p] = i
+	}
+	connected := map[[2]int]bool{}
+	for _, seg := range ico.Segments {
+		a, b := indices[seg.PointA], indices[seg.PointB]
+		connected[[2]int{a, b}] = true
+		connected[[2]int{b, a}] = true
+	}
+	tris := [][3]int{}
+	for a := range points {
+		for b := a + 1; b < len(points); b++ {
+			for c := b + 1; c < len(points); c++ {
+				if connected[[2]int{a, b}] && connected[[2]int{b, c}] && connected[[2]int{c, a}] {
+					tris = append(tris, [3]int{a, b, c})
+				}
+			}
+		}
+	}
+
+	for range subdivisions {
+		midpoints := map[[2]int]int{}
+		midpoint := func(a, b int) int {
+			key := [2]int{min(a, b), max(a, b)}
+			if i, ok := midpoints[key]; ok {
+				return i
+			}
+			p := LerpPoint(0.5, points[a], points[b])
+			p.Normalize()
+			points.Add(p)
+			midpoints[key] = len(points) - 1
+			return midpoints[key]
+		}
+		next := [][3]int{}
+		for _, t := range tris {
+			ab := midpoint(t[0], t[1])
+			bc := midpoint(t[1], t[2])
+			ca := midpoint(t[2], t[0])
+			next = append(next, [3]int{t[0], ab, ca}, [3]int{t[1], bc, ab}, [3]int{t[2], ca, bc}, [3]int{ab, bc, ca})
+		}
+		tris = next
+	}
+
+	shape := NewShape()
+	shape.Points = points
+	edges := map[[2]int]bool{}
+	for _, t := range tris {
+		// orient each face outward.
+		a, b, c := points[t[0]], points[t[1]], points[t[2]]
+		ux, uy, uz := b.X-a.X, b.Y-a.Y, b.Z-a.Z
+		vx, vy, vz := c.X-a.X, c.Y-a.Y, c.Z-a.Z
+		nx, ny, nz := uy*vz-uz*vy, uz*vx-ux*vz, ux*vy-uy*vx
+		if nx*a.X+ny*a.Y+nz*a.Z < 0 {
+			t[1], t[2] = t[2], t[1]
+		}
+		shape.AddFaceByIndex(t[0], t[1], t[2])
+		for i := range 3 {
+			key := [2]int{min(t[i], t[(i+1)%3]), max(t[i], t[(i+1)%3])}
+			if !edges[key] {
+				edges[key] = true
+				shape.AddSegmentByIndex(key[0], key[1])
+			}
+		}
+	}
+	shape.UniScale(radius)
+	shape.Recipe = &Recipe{"icosphere", map[string]float64{"radius": radius, "subdivisions": float64(subdivisions)}}
+	return shape
+}
+
+// Asteroid creates a lumpy, low poly rock shape by displacing the points of an icosphere with noise.
+// irregularity is how far points may move in or out, as a fraction of the radius. 0.3 is a good start.
+// Low subdivisions, such as 1 or 2, give the faceted low poly look.
+// The same seed will always produce the same rock.
+func Asteroid(radius, irregularity float64, subdivisions int, seed int64) *Shape {
+	rng := rand.New(rand.NewSource(seed))
+	ox, oy, oz := rng.Float64()*1000, rng.Float64()*1000, rng.Float64()*1000
+	shape := Icosphere(1, subdivisions)
+	for _, p := range shape.Points {
+		n := 0.0
+		amp := 1.0
+		freq := 1.0
+		for range 3 {
+			n += noise.Simplex3(ox+p.X*freq, oy+p.Y*freq, oz+p.Z*freq) * amp
+			amp /= 2
+			freq *= 2
+		}
+		p.UniScale(1 + n/1.75*irregularity)
+	}
+	shape.UniScale(radius)
+	shape.Recipe = &Recipe{"asteroid", map[string]float64{
+		"radius": radius, "irregularity": irregularity, "subdivisions": float64(subdivisions), "seed": float64(seed),
+	}}
+	return shape
+}
+
+//////////////////////////////
+// Archimedean Solids
+//////////////////////////////
+
+// TruncatedIcosahedron creates a truncated icosahedron (soccer ball) shape,
+// made of 12 pentagons and 20 hexagons.
+func TruncatedIcosahedron(radius float64) *Shape {
+	phi := math.Phi
+	points := signedPermutations(0, 1, 3*phi, true)
+	points = append(points, signedPermutations(1, 2+phi, 2*phi, true)...)
+	points = append(points, signedPermutations(phi, 2, 2*phi+1, true)...)
+	return uniformPolyhedron(points, radius)
+}
+
+// Cuboctahedron creates a cuboctahedron shape, made of 8 triangles and 6 squares.
+func Cuboctahedron(radius float64) *Shape {
+	return uniformPolyhedron(signedPermutations(1, 1, 0, false), radius)
+}
+
+// Icosidodecahedron creates an icosidodecahedron shape, made of 20 triangles and 12 pentagons.
+func Icosidodecahedron(radius float64) *Shape {
+	phi := math.Phi
+	points := signedPermutations(0, 0, phi, true)
+	points = append(points, signedPermutations(0.5, phi/2, phi*phi/2, true)...)
+	return uniformPolyhedron(points, radius)
+}
+
+// TruncatedOctahedron creates a truncated octahedron shape, made of 6 squares and 8 hexagons.
+func TruncatedOctahedron(radius float64) *Shape {
+	return uniformPolyhedron(signedPermutations(0, 1, 2, false), radius)
+}
+
+// TruncatedCube creates a truncated cube shape, made of 8 triangles and 6 octagons.
+func TruncatedCube(radius float64) *Shape {
+	return uniformPolyhedron(signedPermutations(math.Sqrt2-1, 1, 1, false), radius)
+}
+
+// signedPermutations returns every sign variation of the permutations of x, y, z, with duplicates removed.
+// If even is true, only the even (cyclic) permutations are used.
+func signedPermutations(x, y, z float64, even bool) PointList {
+	perms := [][3]float64{{x, y, z}, {z, x, y}, {y, z, x}}
+	if !even {
+		perms = append(perms, [3]float64{x, z, y}, [3]float64{y, x, z}, [3]float64{z, y, x})
+	}
+	signs := func(v float64) []float64 {
+		if v == 0 {
+			return []float64{0}
+		}
+		return []float64{v, -v}
+	}
+	list := NewPointList()
+	for _, perm := range perms {
+		for _, sx := range signs(perm[0]) {
+			for _, sy := range signs(perm[1]) {
+				for _, sz := range signs(perm[2]) {
+					p := NewPoint(sx, sy, sz)
+					found := false
+					for _, q := range list {
+						if p.Distance(q) < 1e-9 {
+							found = true
+							break
+						}
+					}
+					if !found {
+						list.Add(p)
+					}
+				}
+			}
+		}
+	}
+	return list
+}
+
+// uniformPolyhedron creates a shape from the vertices of a uniform polyhedron centered on the origin.
+// All edges of a uniform polyhedron are the same length, so every pair of points separated by the
+// shortest distance in the set is connected. The result is scaled so that its vertices lie on a
+// sphere of the given radius.
+func uniformPolyhedron(points PointList, radius float64) *Shape {
+	shape := NewShape()
+	shape.Points = points
+
+	edge := math.MaxFloat64
+	for i := 0; i < len(points)-1; i++ {
+		for j := i + 1; j < len(points); j++ {
+			edge = math.Min(edge, points[i].Distance(points[j]))
+		}
+	}
+	for i := 0; i < len(points)-1; i++ {
+		for j := i + 1; j < len(points); j++ {
+			if blmath.Equalish(points[i].Distance(points[j]), edge, edge*1e-6) {
+				shape.AddSegmentByIndex(i, j)
+			}
+		}
+	}
+	shape.UniScale(radius / points[0].Magnitude())
+	return shape
+}
+
+//////////////////////////////
+// Navigation
+//////////////////////////////
+
+// Graticule creates a globe of latitude and longitude lines, with a line every latStep and lonStep radians.
+// The poles are on the y-axis, with north at the top (negative y),
+// and longitude 0 faces the viewer (negative z), with east to the right.
+// Gives an empty shape if either step is 0 or less.
+func Graticule(radius, latStep, lonStep float64) *Shape {
+	shape := NewShape()
+	if latStep <= 0 || lonStep <= 0 {
+		return shape
+	}
+	res := 72
+
+	// parallels, working out from the equator. the poles themselves are points, not circles.
+	for i := 0; float64(i)*latStep < math.Pi/2; i++ {
+		lat := float64(i) * latStep
+		for _, l := range []float64{lat, -lat} {
+			p, s := CirclePath(math.Cos(l), res)
+			p.TranslateY(-math.Sin(l))
+			shape.Points = append(shape.Points, p...)
+			shape.Segments = append(shape.Segments, s...)
+			if lat == 0 {
+				break
+			}
+		}
+	}
+
+	// meridians, each running from pole to pole through the equator.
+	north := NewPoint(0, -1, 0)
+	south := NewPoint(0, 1, 0)
+	shape.AddPoint(north)
+	shape.AddPoint(south)
+	for lon := 0.0; lon < blmath.Tau-1e-9; lon += lonStep {
+		equator := latLonPoint(0, lon, 1)
+		shape.AddPoint(equator)
+		addSphereArc(shape, south, equator, blmath.Tau/float64(res))
+		addSphereArc(shape, equator, north, blmath.Tau/float64(res))
+	}
+	shape.UniScale(radius)
+	return shape
+}
+
+// LabeledGraticule creates a Graticule with each line labeled in degrees, using the current font.
+// Parallels are labeled between the first two meridians, and meridians just north of the equator.
+func LabeledGraticule(radius, latStep, lonStep float64) *Shape {
+	shape := Graticule(radius, latStep, lonStep)
+	if latStep <= 0 || lonStep <= 0 {
+		return shape
+	}
+	for i := 0; float64(i)*latStep < math.Pi/2; i++ {
+		lat := float64(i) * latStep
+		deg := math.Round(blmath.RadToDeg(lat))
+		if lat == 0 {
+			shape.AddShape(sphereLabel("0", 0, lonStep/2, radius))
+			continue
+		}
+		shape.AddShape(sphereLabel(fmt.Sprintf("%gN", deg), lat, lonStep/2, radius))
+		shape.AddShape(sphereLabel(fmt.Sprintf("%gS", deg), -lat, lonStep/2, radius))
+	}
+	for lon := 0.0; lon < blmath.Tau-1e-9; lon += lonStep {
+		deg := math.Round(blmath.RadToDeg(lon))
+		text := fmt.Sprintf("%gE", deg)
+		switch {
+		case deg == 0 || deg == 180:
+			text = fmt.Sprintf("%g", deg)
+		case deg > 180:
+			text = fmt.Sprintf("%gW", 360-deg)
+		}
+		shape.AddShape(sphereLabel(text, latStep/2, lon, radius))
+	}
+	return shape
+}
+
+// latLonPoint creates a point on a sphere of the given radius, at the given latitude and longitude in radians,
+// using the same orientation as Graticule.
+func latLonPoint(lat, lon, radius float64) *Point {
+	return NewPoint(
+		math.Cos(lat)*math.Sin(lon)*radius,
+		-math.Sin(lat)*radius,
+		-math.Cos(lat)*math.Cos(lon)*radius,
+	)
+}
+
+// sphereLabel creates a line of text centered on the given latitude and longitude of a sphere,
+// lying flat against the sphere and readable from outside it.
+func sphereLabel(text string, lat, lon, radius float64) *Shape {
+	label := NewString(text).AsLine()
+	label.TranslateZ(-radius)
+	label.RotateX(lat)
+	label.RotateY(-lon)
+	return label
+}
+
+// CompassRose creates an eight pointed compass rose lying flat on the xz plane,
+// with north pointing along the positive z-axis and east along the positive x-axis.
+// The cardinal points reach the given radius, and the intercardinal points are shorter.
+func CompassRose(radius float64) *Shape {
+	shape := NewShape()
+	center := NewPoint(0, 0, 0)
+	shape.AddPoint(center)
+	var first, prev *Point
+	for i := range 16 {
+		// alternating tips and the valleys between them, clockwise from north.
+		angle := float64(i) * math.Pi / 8
+		r := 0.15
+		if i%4 == 0 {
+			r = 1
+		} else if i%2 == 0 {
+			r = 0.6
+		}
+		p := NewPoint(math.Sin(angle)*r, 0, math.Cos(angle)*r)
+		shape.AddPoint(p)
+		if i%2 == 0 {
+			shape.AddSegmentByPoints(center, p)
+		}
+		if prev != nil {
+			shape.AddSegmentByPoints(prev, p)
+		} else {
+			first = p
+		}
+		prev = p
+	}
+	shape.AddSegmentByPoints(prev, first)
+	shape.AddShape(Circle(0.75, 64))
+	shape.UniScale(radius)
+	return shape
+}
+
+// LabeledCompassRose creates a CompassRose with the cardinal points labeled N, E, S and W, using the current font.
+// The labels lie flat beyond the tips of the rose, readable when looking north.
+func LabeledCompassRose(radius float64) *Shape {
+	shape := CompassRose(radius)
+	for i, text := range []string{"N", "E", "S", "W"} {
+		angle := float64(i) * math.Pi / 2
+		dist := radius + world.FontSize
+		label := NewString(text).AsLine()
+		label.RotateX(math.Pi / 2)
+		label.Translate(math.Sin(angle)*dist, 0, math.Cos(angle)*dist)
+		shape.AddShape(label)
+	}
+	return shape
+}
+
+//////////////////////////////
+// Procedural Structures
+//////////////////////////////
+
+// SphericalVoronoi creates a 3d sphere divided into the Voronoi cells of a number of random sites
+// on its surface. Only the cell edges are included, each one following the curve of the sphere.
+// The same seed will always produce the same cells.
+func SphericalVoronoi(radius float64, sites int, seed int64) *Shape {
+	rng := rand.New(rand.NewSource(seed))
+	points := NewPointList()
+	for range sites {
+		// https://mathworld.wolfram.com/SpherePointPicking.html
+		u := rng.Float64()*2 - 1
+		t := rng.Float64() * blmath.Tau
+		points.AddXYZ(math.Sqrt(1-u*u)*math.Cos(t), math.Sqrt(1-u*u)*math.Sin(t), u)
+	}
+
+	// the Delaunay triangulation of points on a sphere is their convex hull.
+	// each triangle's outward normal is the direction of its circumcenter - a Voronoi vertex.
+	faces := convexHull(points)
+	shape := NewShape()
+	edges := map[[2]int][]int{}
+	for i, f := range faces {
+		shape.AddXYZ(f.nx, f.ny, f.nz)
+		for _, e := range [][2]int{{f.a, f.b}, {f.b, f.c}, {f.c, f.a}} {
+			key := [2]int{min(e[0], e[1]), max(e[0], e[1])}
+			edges[key] = append(edges[key], i)
+		}
+	}
+
+	// each Delaunay edge is crossed by the Voronoi edge joining its two triangles' centers.
+	// edges are sorted so the arcs are always added in the same order.
+	keys := make([][2]int, 0, len(edges))
+	for key := range edges {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b [2]int) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+	for _, key := range keys {
+		if tris := edges[key]; len(tris) == 2 {
+			addSphereArc(shape, shape.Points[tris[0]], shape.Points[tris[1]], math.Pi/36)
+		}
+	}
+	shape.UniScale(radius)
+	return shape
+}
+
+// Gyroid creates a cube shaped lattice of a gyroid triply periodic minimal surface.
+// size is the width of the cube and cells is the number of repeats of the pattern across it.
+// If thickness is greater than zero, the surface is thickened into a sheet with two sides.
+// Useful values are from 0 to about 1. If it is zero, the surface itself is used.
+func Gyroid(size float64, cells int, thickness float64) *Shape {
+	return tpms(func(x, y, z float64) float64 {
+		return math.Sin(x)*math.Cos(y) + math.Sin(y)*math.Cos(z) + math.Sin(z)*math.Cos(x)
+	}, size, cells, thickness)
+}
+
+// SchwarzP creates a cube shaped lattice of a Schwarz P (primitive) triply periodic minimal surface.
+// See Gyroid for a description of the parameters.
+func SchwarzP(size float64, cells int, thickness float64) *Shape {
+	return tpms(func(x, y, z float64) float64 {
+		return math.Cos(x) + math.Cos(y) + math.Cos(z)
+	}, size, cells, thickness)
+}
+
+// SchwarzD creates a cube shaped lattice of a Schwarz D (diamond) triply periodic minimal surface.
+// See Gyroid for a description of the parameters.
+func SchwarzD(size float64, cells int, thickness float64) *Shape {
+	return tpms(func(x, y, z float64) float64 {
+		sx, sy, sz := math.Sin(x), math.Sin(y), math.Sin(z)
+		cx, cy, cz := math.Cos(x), math.Cos(y), math.Cos(z)
+		return sx*sy*sz + sx*cy*cz + cx*sy*cz + cx*cy*sz
+	}, size, cells, thickness)
+}
+
+// Neovius creates a cube shaped lattice of a Neovius triply periodic minimal surface.
+// See Gyroid for a description of the parameters.
+func Neovius(size float64, cells int, thickness float64) *Shape {
+	return tpms(func(x, y, z float64) float64 {
+		cx, cy, cz := math.Cos(x), math.Cos(y), math.Cos(z)
+		return (3*(cx+cy+cz) + 4*cx*cy*cz) / 3
+	}, size, cells, thickness)
+}
+
+// tpms creates an isosurface of a triply periodic function with a period of Tau,
+// repeated cells times across a cube of the given size.
+func tpms(f func(x, y, z float64) float64, size float64, cells int, thickness float64) *Shape {
+	res := cells * 12
+	scale := blmath.Tau * float64(cells) / size
+	field := func(x, y, z float64) float64 {
+		v := f(x*scale, y*scale, z*scale)
+		if thickness > 0 {
+			return thickness/2 - math.Abs(v)
+		}
+		return v
+	}
+	return Isosurface(field, CenteredBounds(size, size, size), res, 0)
+}
+
+// addSphereArc adds segments to the shape following the great circle between two points
+// on a unit sphere, with no single segment covering more than maxAngle.
+func addSphereArc(shape *Shape, a, b *Point, maxAngle float64) {
+	dot := blmath.Clamp(a.X*b.X+a.Y*b.Y+a.Z*b.Z, -1, 1)
+	angle := math.Acos(dot)
+	count := max(1, int(math.Ceil(angle/maxAngle)))
+	p0 := a
+	for i := 1; i < count; i++ {
+		// spherical interpolation, so the points are evenly spaced along the arc.
+		t := float64(i) / float64(count)
+		ta := math.Sin((1-t)*angle) / math.Sin(angle)
+		tb := math.Sin(t*angle) / math.Sin(angle)
+		p1 := NewPoint(a.X*ta+b.X*tb, a.Y*ta+b.Y*tb, a.Z*ta+b.Z*tb)
+		shape.AddPoint(p1)
+		shape.AddSegmentByPoints(p0, p1)
+		p0 = p1
+	}
+	shape.AddSegmentByPoints(p0, b)
+}
@@ -0,0 +1,93 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import "math"
+
+// SpatialGrid is a uniform grid spatial index over a set of points.
+// It allows for fast lookups of points near a given location without testing every point.
+type SpatialGrid struct {
+	CellSize float64
+	cells    map[[3]int]PointList
+}
+
+// NewSpatialGrid creates a new spatial grid containing the given points.
+// For best performance, cellSize should be close to the radius that will be used for lookups.
+// A cellSize of 0 or less puts every point in a single cell.
+func NewSpatialGrid(points PointList, cellSize float64) *SpatialGrid {
+	if cellSize <= 0 {
+		cellSize = math.Inf(1)
+	}
+	grid := &SpatialGrid{
+		CellSize: cellSize,
+		cells:    map[[3]int]PointList{},
+	}
+	for _, p := range points {
+		grid.Add(p)
+	}
+	return grid
+}
+
+// Add adds a point to the grid.
+// The grid does not track changes to the point, so points that move after being added must be re-indexed.
+func (g *SpatialGrid) Add(p *Point) {
+	key := g.cellKey(p.X, p.Y, p.Z)
+	g.cells[key] = append(g.cells[key], p)
+}
+
+// Neighbors returns all points within radius of the given point, not including the point itself.
+func (g *SpatialGrid) Neighbors(p *Point, radius float64) PointList {
+	list := NewPointList()
+	g.visit(p, radius, func(other *Point) {
+		list.Add(other)
+	})
+	return list
+}
+
+// CountNeighbors returns the number of points within radius of the given point, not including the point itself.
+func (g *SpatialGrid) CountNeighbors(p *Point, radius float64) int {
+	count := 0
+	g.visit(p, radius, func(other *Point) {
+		count++
+	})
+	return count
+}
+
+// Nearest returns the closest point to the given point, not including the point itself,
+// searching no further than maxDist. Returns nil if no point is found.
+func (g *SpatialGrid) Nearest(p *Point, maxDist float64) *Point {
+	var nearest *Point
+	best := math.MaxFloat64
+	g.visit(p, maxDist, func(other *Point) {
+		dist := p.Distance(other)
+		if dist < best {
+			best = dist
+			nearest = other
+		}
+	})
+	return nearest
+}
+
+// visit calls f for every point within radius of p, excluding p itself.
+func (g *SpatialGrid) visit(p *Point, radius float64, f func(*Point)) {
+	min := g.cellKey(p.X-radius, p.Y-radius, p.Z-radius)
+	max := g.cellKey(p.X+radius, p.Y+radius, p.Z+radius)
+	for x := min[0]; x <= max[0]; x++ {
+		for y := min[1]; y <= max[1]; y++ {
+			for z := min[2]; z <= max[2]; z++ {
+				for _, other := range g.cells[[3]int{x, y, z}] {
+					if other != p && p.Distance(other) <= radius {
+						f(other)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (g *SpatialGrid) cellKey(x, y, z float64) [3]int {
+	return [3]int{
+		int(math.Floor(x / g.CellSize)),
+		int(math.Floor(y / g.CellSize)),
+		int(math.Floor(z / g.CellSize)),
+	}
+}
@@ -0,0 +1,183 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"errors"
+	"reflect"
+)
+
+// WorldState is a copy of all the world's settings: the camera, clipping, fog, water level, color, font,
+// and everything else set by the Set... functions, except the drawing context and depth functions.
+// It can be saved as JSON with an artwork, so the exact setup of a frame can be restored later with ApplyState,
+// such as to render it again at a higher resolution.
+type WorldState struct {
+	FL               float64      `json:"fl"`
+	CX               float64      `json:"cx"`
+	CY               float64      `json:"cy"`
+	CZ               float64      `json:"cz"`
+	Width            float64      `json:"width,omitempty"`
+	Height           float64      `json:"height,omitempty"`
+	NearZ            float64      `json:"nearZ"`
+	FarZ             float64      `json:"farZ"`
+	FogActive        bool         `json:"fogActive"`
+	NearFog          float64      `json:"nearFog"`
+	FarFog           float64      `json:"farFog"`
+	FogCurve         FogCurveType `json:"fogCurve"`
+	FogLayers        []FogLayer   `json:"fogLayers"`
+	WaterLevelActive bool         `json:"waterLevelActive"`
+	WaterLevelTop    float64      `json:"waterLevelTop"`
+	WaterLevelBottom float64      `json:"waterLevelBottom"`
+	R                float64      `json:"r"`
+	G                float64      `json:"g"`
+	B                float64      `json:"b"`
+	// Font is the name of the built in font, such as "FontAsteroid".
+	Font            string     `json:"font"`
+	FontSize        float64    `json:"fontSize"`
+	FontSpacing     float64    `json:"fontSpacing"`
+	LabelPoints     bool       `json:"labelPoints"`
+	DensityActive   bool       `json:"densityActive"`
+	DensityRadius   float64    `json:"densityRadius"`
+	DensityCount    float64    `json:"densityCount"`
+	Time            float64    `json:"time"`
+	BoilAmplitude   float64    `json:"boilAmplitude"`
+	BoilFPS         float64    `json:"boilFPS"`
+	Workers         int        `json:"workers"`
+	WidthSpace      WidthSpace `json:"widthSpace"`
+	MaxStrokeLength float64    `json:"maxStrokeLength"`
+	MaxStrokeDepth  float64    `json:"maxStrokeDepth"`
+	DepthSort       bool       `json:"depthSort"`
+	BackfaceCulling bool       `json:"backfaceCulling"`
+	PixelSnap       bool       `json:"pixelSnap"`
+	Supersample     float64    `json:"supersample"`
+}
+
+// SnapshotState returns a copy of the world's current settings.
+func SnapshotState() WorldState {
+	layers := []FogLayer{}
+	for _, layer := range world.FogLayers {
+		layers = append(layers, *layer)
+	}
+	font := ""
+	for _, f := range builtinFonts() {
+		if sameFont(f.font, world.Font) {
+			font = f.name
+		}
+	}
+	return WorldState{
+		FL:               world.FL,
+		CX:               world.CX,
+		CY:               world.CY,
+		CZ:               world.CZ,
+		Width:            world.Width,
+		Height:           world.Height,
+		NearZ:            world.NearZ,
+		FarZ:             world.FarZ,
+		FogActive:        world.FogActive,
+		NearFog:          world.NearFog,
+		FarFog:           world.FarFog,
+		FogCurve:         world.FogCurve,
+		FogLayers:        layers,
+		WaterLevelActive: world.WaterLevelActive,
+		WaterLevelTop:    world.WaterLevelTop,
+		WaterLevelBottom: world.WaterLevelBottom,
+		R:                world.R,
+		G:                world.G,
+		B:                world.B,
+		Font:             font,
+		FontSize:         world.FontSize,
+		FontSpacing:      world.FontSpacing,
+		LabelPoints:      world.LabelPoints,
+		DensityActive:    world.DensityActive,
+		DensityRadius:    world.DensityRadius,
+		DensityCount:     world.DensityCount,
+		Time:             world.Time,
+		BoilAmplitude:    world.BoilAmplitude,
+		BoilFPS:          world.BoilFPS,
+		Workers:          world.Workers,
+		WidthSpace:       world.WidthSpace,
+		MaxStrokeLength:  world.MaxStrokeLength,
+		MaxStrokeDepth:   world.MaxStrokeDepth,
+		DepthSort:        world.DepthSort,
+		BackfaceCulling:  world.BackfaceCulling,
+		PixelSnap:        world.PixelSnap,
+		Supersample:      world.Supersample,
+	}
+}
+
+// ApplyState sets all the world's settings from a state returned by SnapshotState,
+// keeping the current drawing context, depth functions and occluders.
+// Returns an error, without changing anything, if the state's font is not a built in font.
+// An empty font name keeps the current font.
+func ApplyState(ws WorldState) error {
+	font := world.Font
+	if ws.Font != "" {
+		found := false
+		for _, f := range builtinFonts() {
+			if f.name == ws.Font {
+				font = f.font
+				found = true
+			}
+		}
+		if !found {
+			return errors.New("unable to apply state: unknown font " + ws.Font)
+		}
+	}
+	if ws.Supersample == 0 {
+		// states saved before supersampling was added.
+		ws.Supersample = 1
+	}
+	layers := []*FogLayer{}
+	for _, layer := range ws.FogLayers {
+		l := layer
+		layers = append(layers, &l)
+	}
+	world = worldDef{
+		FL:               ws.FL,
+		CX:               ws.CX,
+		CY:               ws.CY,
+		CZ:               ws.CZ,
+		Width:            ws.Width,
+		Height:           ws.Height,
+		NearZ:            ws.NearZ,
+		FarZ:             ws.FarZ,
+		FogActive:        ws.FogActive,
+		NearFog:          ws.NearFog,
+		FarFog:           ws.FarFog,
+		WaterLevelActive: ws.WaterLevelActive,
+		WaterLevelTop:    ws.WaterLevelTop,
+		WaterLevelBottom: ws.WaterLevelBottom,
+		R:                ws.R,
+		G:                ws.G,
+		B:                ws.B,
+		Context:          world.Context,
+		Font:             font,
+		FontSize:         ws.FontSize,
+		FontSpacing:      ws.FontSpacing,
+		LabelPoints:      ws.LabelPoints,
+		DensityActive:    ws.DensityActive,
+		DensityRadius:    ws.DensityRadius,
+		DensityCount:     ws.DensityCount,
+		Time:             ws.Time,
+		BoilAmplitude:    ws.BoilAmplitude,
+		BoilFPS:          ws.BoilFPS,
+		Workers:          ws.Workers,
+		WidthSpace:       ws.WidthSpace,
+		FogCurve:         ws.FogCurve,
+		FogLayers:        layers,
+		MaxStrokeLength:  ws.MaxStrokeLength,
+		MaxStrokeDepth:   ws.MaxStrokeDepth,
+		DepthSort:        ws.DepthSort,
+		BackfaceCulling:  ws.BackfaceCulling,
+		PixelSnap:        ws.PixelSnap,
+		Supersample:      ws.Supersample,
+		DepthWidthFunc:   world.DepthWidthFunc,
+		DepthAlphaFunc:   world.DepthAlphaFunc,
+		Occluders:        world.Occluders,
+	}
+	return nil
+}
+
+// sameFont returns whether two fonts use the same glyph data.
+func sameFont(a, b FontType) bool {
+	return reflect.ValueOf(a.data).Pointer() == reflect.ValueOf(b.data).Pointer()
+}
@@ -0,0 +1,55 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"fmt"
+	"time"
+)
+
+// RenderStats counts what has been drawn since the stats were last reset.
+type RenderStats struct {
+	// Segments is the number of segments stroked.
+	Segments int
+	// Points is the number of points rendered.
+	Points int
+	// Culled is the number of segments and points skipped because they were outside the clipping planes,
+	// or hidden by occluders.
+	Culled int
+}
+
+// renderStats holds the counts since the last call to ResetRenderStats.
+var renderStats RenderStats
+
+// GetRenderStats returns the counts of what has been drawn since the last call to ResetRenderStats.
+func GetRenderStats() RenderStats {
+	return renderStats
+}
+
+// ResetRenderStats sets all the render counts back to zero, such as at the start of a frame.
+func ResetRenderStats() {
+	renderStats = RenderStats{}
+}
+
+// statsOverlay creates a shape listing the render stats and the time taken, pinned to the top left of the screen.
+func statsOverlay(stats RenderStats, elapsed time.Duration) *Shape {
+	size, spacing := world.FontSize, world.FontSpacing
+	defer SetFont(world.Font, size, spacing)
+	SetFont(world.Font, 8, 0.2)
+
+	lines := []string{
+		fmt.Sprintf("SEGMENTS %d", stats.Segments),
+		fmt.Sprintf("POINTS %d", stats.Points),
+		fmt.Sprintf("CULLED %d", stats.Culled),
+		fmt.Sprintf("TIME %.1f MS", float64(elapsed.Microseconds())/1000),
+	}
+	shape := NewShape()
+	for i, line := range lines {
+		s := NewString(line).AsLine()
+		b := s.GetBounds()
+		// left aligned, one line every two font sizes.
+		s.Translate(-b.MinX, float64(i)*world.FontSize*2, 0)
+		shape.AddShape(s)
+	}
+	shape.PinToCorner(TopLeft, 0, 0, 10)
+	return shape
+}
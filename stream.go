@@ -0,0 +1,209 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ShapeWriter writes a shape in the format used by Save and LoadShape, a point or segment at a time,
+// so shapes far too large to hold in memory can be generated and saved.
+// The format gives the number of points and segments before them, so they are spooled to temporary files
+// as they are added, and written out in full by Close.
+type ShapeWriter struct {
+	w           io.Writer
+	points      *os.File
+	segments    *os.File
+	pointBuf    *bufio.Writer
+	segmentBuf  *bufio.Writer
+	numPoints   int
+	numSegments int
+	err         error
+}
+
+// NewShapeWriter creates a new shape writer that writes to w when it is closed.
+func NewShapeWriter(w io.Writer) (*ShapeWriter, error) {
+	points, err := os.CreateTemp("", "wire-points-*")
+	if err != nil {
+		return nil, errors.New("unable to create shape writer: " + err.Error())
+	}
+	segments, err := os.CreateTemp("", "wire-segments-*")
+	if err != nil {
+		points.Close()
+		os.Remove(points.Name())
+		return nil, errors.New("unable to create shape writer: " + err.Error())
+	}
+	return &ShapeWriter{
+		w:          w,
+		points:     points,
+		segments:   segments,
+		pointBuf:   bufio.NewWriter(points),
+		segmentBuf: bufio.NewWriter(segments),
+	}, nil
+}
+
+// AddPoint adds a point and returns its index, for use with AddSegment.
+func (sw *ShapeWriter) AddPoint(p *Point) int {
+	return sw.AddXYZ(p.X, p.Y, p.Z)
+}
+
+// AddXYZ adds a point and returns its index, for use with AddSegment.
+func (sw *ShapeWriter) AddXYZ(x, y, z float64) int {
+	if sw.err == nil {
+		_, sw.err = fmt.Fprintf(sw.pointBuf, "%f %f %f\n", x, y, z)
+	}
+	sw.numPoints++
+	return sw.numPoints - 1
+}
+
+// AddSegment adds a segment between the points with the given indices.
+// Segments may be added at any time, but must only use points that have already been added.
+func (sw *ShapeWriter) AddSegment(a, b int) {
+	if sw.err != nil {
+		return
+	}
+	if a < 0 || a >= sw.numPoints || b < 0 || b >= sw.numPoints {
+		sw.err = fmt.Errorf("invalid segment index, should be from zero to %d", sw.numPoints-1)
+		return
+	}
+	_, sw.err = fmt.Fprintf(sw.segmentBuf, "%d %d\n", a, b)
+	sw.numSegments++
+}
+
+// Close writes the whole shape to the writer and removes the temporary files.
+// Returns the first error that happened while adding to the shape or writing it.
+func (sw *ShapeWriter) Close() error {
+	defer func() {
+		sw.points.Close()
+		sw.segments.Close()
+		os.Remove(sw.points.Name())
+		os.Remove(sw.segments.Name())
+	}()
+	write := func(count int, buf *bufio.Writer, file *os.File) {
+		if sw.err != nil {
+			return
+		}
+		if sw.err = buf.Flush(); sw.err != nil {
+			return
+		}
+		if _, sw.err = file.Seek(0, io.SeekStart); sw.err != nil {
+			return
+		}
+		if _, sw.err = io.WriteString(sw.w, strconv.Itoa(count)+"\n"); sw.err != nil {
+			return
+		}
+		_, sw.err = io.Copy(sw.w, file)
+	}
+	write(sw.numPoints, sw.pointBuf, sw.points)
+	write(sw.numSegments, sw.segmentBuf, sw.segments)
+	if sw.err != nil {
+		return errors.New("unable to write shape: " + sw.err.Error())
+	}
+	return nil
+}
+
+// ShapeChunk is part of a shape read by ReadShapeStream: either some of its points, or some of its segments.
+type ShapeChunk struct {
+	// Start is the index of the first point in Points, within the whole shape.
+	Start  int
+	Points PointList
+	// Segments are pairs of indices of points within the whole shape.
+	Segments [][2]int
+}
+
+// ReadShapeStream reads a shape in the format used by Save and LoadShape, without holding the whole shape in memory.
+// fn is called with chunks of up to chunkSize points, in order, followed by chunks of up to chunkSize segments.
+// Reading stops with the first error, either in the file or returned by fn.
+func ReadShapeStream(r io.Reader, chunkSize int, fn func(chunk ShapeChunk) error) error {
+	chunkSize = max(1, chunkSize)
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	next := func() ([]string, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return nil, errors.New("unable to load shape: " + err.Error())
+			}
+			return nil, fmt.Errorf("unable to parse shape: file ends at line %d", lineNum)
+		}
+		lineNum++
+		return strings.Fields(scanner.Text()), nil
+	}
+	count := func() (int, error) {
+		fields, err := next()
+		if err != nil {
+			return 0, err
+		}
+		if len(fields) != 1 {
+			return 0, fmt.Errorf("unable to parse shape: expected a count on line %d", lineNum)
+		}
+		n, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse shape: line %d: %s", lineNum, err)
+		}
+		return n, nil
+	}
+
+	numPoints, err := count()
+	if err != nil {
+		return err
+	}
+	chunk := ShapeChunk{Points: NewPointList()}
+	for i := range numPoints {
+		fields, err := next()
+		if err != nil {
+			return err
+		}
+		if len(fields) < 3 {
+			return fmt.Errorf("unable to parse shape: line %d: point needs three coords", lineNum)
+		}
+		coords := [3]float64{}
+		for j := range coords {
+			if coords[j], err = strconv.ParseFloat(fields[j], 64); err != nil {
+				return fmt.Errorf("unable to parse shape: line %d: %s", lineNum, err)
+			}
+		}
+		chunk.Points.AddXYZ(coords[0], coords[1], coords[2])
+		if len(chunk.Points) == chunkSize || i == numPoints-1 {
+			if err := fn(chunk); err != nil {
+				return err
+			}
+			chunk = ShapeChunk{Start: i + 1, Points: NewPointList()}
+		}
+	}
+
+	numSegments, err := count()
+	if err != nil {
+		return err
+	}
+	chunk = ShapeChunk{}
+	for i := range numSegments {
+		fields, err := next()
+		if err != nil {
+			return err
+		}
+		if len(fields) < 2 {
+			return fmt.Errorf("unable to parse shape: line %d: segment needs two indices", lineNum)
+		}
+		a, errA := strconv.Atoi(fields[0])
+		b, errB := strconv.Atoi(fields[1])
+		if errA != nil || errB != nil {
+			return fmt.Errorf("unable to parse shape: line %d: bad segment indices", lineNum)
+		}
+		if a < 0 || a >= numPoints || b < 0 || b >= numPoints {
+			return fmt.Errorf("unable to parse shape: line %d: invalid segment index, should be from zero to %d", lineNum, numPoints-1)
+		}
+		chunk.Segments = append(chunk.Segments, [2]int{a, b})
+		if len(chunk.Segments) == chunkSize || i == numSegments-1 {
+			if err := fn(chunk); err != nil {
+				return err
+			}
+			chunk = ShapeChunk{}
+		}
+	}
+	return nil
+}
@@ -2,7 +2,11 @@
 package wire
 
 import (
+	"errors"
+	"fmt"
 	"math"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -18,17 +22,25 @@ type String struct {
 	Orig        string
 	Letters     []*Shape
 	AspectRatio float64
+	// Descent is how far descenders, such as the tail of a g, reach below the baseline, relative to the font size.
+	Descent float64
+	// RightToLeft lays the letters out from right to left in AsLine and AsArc, for right to left scripts.
+	RightToLeft bool
 }
 
 // NewString creates a new 3d string object.
+// Characters the font has no glyph for are drawn in upper case if the font has that instead.
 func NewString(str string) *String {
-	str = strings.ToUpper(str)
 	paths := []*Shape{}
 	for _, s := range str {
-		char := ParseChar(string(s), world.Font)
+		c := string(s)
+		if _, ok := world.Font.data[c]; !ok {
+			c = strings.ToUpper(c)
+		}
+		char := ParseChar(c, world.Font)
 		paths = append(paths, char)
 	}
-	return &String{str, paths, world.Font.width / world.Font.height}
+	return &String{str, paths, world.Font.width / world.Font.height, world.Font.descent / world.Font.width, false}
 }
 
 // ParseChar parses a single character into a single 3d shape.
@@ -36,8 +48,12 @@ func NewString(str string) *String {
 // Height will depend on the font.
 // Each character is scaled to 100 units wide on creation (-50 to +50).
 // The string shape can be scaled further later.
+// Characters the font doesn't have are drawn as a box with an X through it. See MissingGlyphShape.
 func ParseChar(char string, fontData FontType) *Shape {
-	charData := fontData.data[char]
+	charData, ok := fontData.data[char]
+	if !ok {
+		return missingGlyph(fontData)
+	}
 	strokes := strings.Split(charData, ":")
 	shape := NewShape()
 	index := 0
@@ -48,7 +64,7 @@ func ParseChar(char string, fontData FontType) *Shape {
 			xi, _ := strconv.ParseInt(string(coord[0]), 16, 64)
 			yi, _ := strconv.ParseInt(string(coord[1]), 16, 64)
 			x := float64(2*xi)/fontData.width - 1.0
-			y := (fontData.height - float64(2*yi)) / fontData.width
+			y := (fontData.height - 2*(float64(yi)-fontData.descent)) / fontData.width
 			shape.AddXYZ(x, y, 0)
 			if i > 0 {
 				shape.AddSegmentByIndex(index-1, index)
@@ -60,6 +76,97 @@ func ParseChar(char string, fontData FontType) *Shape {
 	return shape
 }
 
+// MissingGlyphShape creates the shape used in place of characters the current font doesn't have:
+// a box the size of a character, with an X through it.
+func MissingGlyphShape() *Shape {
+	return missingGlyph(world.Font)
+}
+
+// missingGlyph creates the missing glyph shape for the given font.
+func missingGlyph(fontData FontType) *Shape {
+	h := fontData.height / fontData.width
+	shape := NewShape()
+	shape.AddXYZ(-1, -h, 0)
+	shape.AddXYZ(1, -h, 0)
+	shape.AddXYZ(1, h, 0)
+	shape.AddXYZ(-1, h, 0)
+	shape.AddSegmentByIndex(0, 1)
+	shape.AddSegmentByIndex(1, 2)
+	shape.AddSegmentByIndex(2, 3)
+	shape.AddSegmentByIndex(3, 0)
+	shape.AddSegmentByIndex(0, 2)
+	shape.AddSegmentByIndex(1, 3)
+	shape.UniScale(world.FontSize / 2)
+	return shape
+}
+
+// ValidateFont checks that every glyph in a font can be parsed: each coordinate is two hex digits within
+// the font's width and height, and no stroke is degenerate, with two points in a row in the same place.
+// Returns an error describing every problem found, or nil if the font is valid.
+func ValidateFont(fontData FontType) error {
+	problems := []string{}
+	chars := []string{}
+	for char := range fontData.data {
+		chars = append(chars, char)
+	}
+	sort.Strings(chars)
+	for _, char := range chars {
+		for _, stroke := range strings.Split(fontData.data[char], ":") {
+			coords := strings.Fields(stroke)
+			if len(coords) == 0 {
+				problems = append(problems, fmt.Sprintf("%q: empty stroke", char))
+				continue
+			}
+			for i, coord := range coords {
+				if len(coord) != 2 {
+					problems = append(problems, fmt.Sprintf("%q: bad coordinate %q", char, coord))
+					continue
+				}
+				x, errX := strconv.ParseInt(coord[0:1], 16, 64)
+				y, errY := strconv.ParseInt(coord[1:2], 16, 64)
+				if errX != nil || errY != nil {
+					problems = append(problems, fmt.Sprintf("%q: bad coordinate %q", char, coord))
+					continue
+				}
+				if float64(x) > fontData.width || float64(y) > fontData.height+fontData.descent {
+					problems = append(problems, fmt.Sprintf("%q: coordinate %q out of range", char, coord))
+				}
+				if i > 0 && coord == coords[i-1] {
+					problems = append(problems, fmt.Sprintf("%q: zero length segment at %q", char, coord))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid font: " + strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// namedFont is a built in font and the name of its variable.
+type namedFont struct {
+	name string
+	font FontType
+}
+
+// builtinFonts returns every built in font, with its name.
+func builtinFonts() []namedFont {
+	return []namedFont{
+		{"FontArcade", FontArcade},
+		{"FontAsteroid", FontAsteroid},
+	}
+}
+
+// ValidateFonts checks every built in font with ValidateFont.
+func ValidateFonts() error {
+	for _, f := range builtinFonts() {
+		if err := ValidateFont(f.font); err != nil {
+			return errors.New(f.name + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
 // AsCylinder creates a single shape consisting of the all the chars in the string wrapped around a cylinder.
 func (s *String) AsCylinder(radius float64) *Shape {
 	shape := NewShape()
@@ -95,7 +202,7 @@ func (s *String) AsVCylinder(radius float64) *Shape {
 func (s *String) AsLine() *Shape {
 	shape := NewShape()
 	spacing := world.FontSize * world.FontSpacing
-	for i, pl := range s.Letters {
+	for i, pl := range s.ordered() {
 		pl.TranslateX(world.FontSize/2 + (world.FontSize+spacing)*float64(i))
 		shape.Points = append(shape.Points, pl.Points...)
 		shape.Segments = append(shape.Segments, pl.Segments...)
@@ -107,28 +214,82 @@ func (s *String) AsLine() *Shape {
 
 // AsVLine creates a single shape consisting of all the chars in the string laid out in a single vertical line.
 func (s *String) AsVLine() *Shape {
+	return s.AsVerticalLine(world.FontSpacing)
+}
+
+// AsVerticalLine creates a single shape consisting of all the chars in the string stacked from top to bottom,
+// with the given spacing between them, relative to the font size, as with SetFont.
+func (s *String) AsVerticalLine(spacing float64) *Shape {
 	shape := NewShape()
-	fontHeight := world.FontSize / s.AspectRatio
-	spacing := world.FontSize * world.FontSpacing
+	// each letter gets room for its descenders, so they don't run into the letter below.
+	fontHeight := world.FontSize/s.AspectRatio + world.FontSize*s.Descent
+	gap := world.FontSize * spacing
 	for i, pl := range s.Letters {
-		pl.TranslateY(fontHeight/2 + (fontHeight+spacing)*float64(i))
+		pl.TranslateY(fontHeight/2 + (fontHeight+gap)*float64(i))
 		shape.Points = append(shape.Points, pl.Points...)
 		shape.Segments = append(shape.Segments, pl.Segments...)
 	}
 	mult := float64(len(s.Letters))
-	shape.TranslateY(-(fontHeight+spacing)*mult/2 + spacing/2)
+	shape.TranslateY(-(fontHeight+gap)*mult/2 + gap/2)
 	return shape
 }
 
+// AsArc creates a single shape consisting of all the chars in the string laid out along an arc of the given
+// radius on the xy plane, centered on the origin, arching over the top of the circle. The letters are spaced
+// as in AsLine, measured along the arc. If upright is true, each letter stands straight up wherever it is
+// on the arc, otherwise the letters lean to follow the curve, with their bases toward the center.
+func (s *String) AsArc(radius float64, upright bool) *Shape {
+	shape := NewShape()
+	spacing := world.FontSize * world.FontSpacing
+	step := (world.FontSize + spacing) / radius
+	start := -step * float64(len(s.Letters)-1) / 2
+	// letters sit on the arc by their baselines.
+	baseline := world.FontSize / s.AspectRatio / 2
+	for i, pl := range s.ordered() {
+		angle := start + step*float64(i)
+		if upright {
+			pl.Translate(math.Sin(angle)*radius, -math.Cos(angle)*radius-baseline, 0)
+		} else {
+			// placed at the top of the circle, then turned around the center to its place on the arc.
+			pl.TranslateY(-radius - baseline)
+			pl.RotateZ(angle)
+		}
+		shape.Points = append(shape.Points, pl.Points...)
+		shape.Segments = append(shape.Segments, pl.Segments...)
+	}
+	return shape
+}
+
+// RotateLetters rotates each letter of the string around its own center. Call it before one of the As... methods
+// to turn the letters within the layout, such as laying them on their backs, or spinning each one for animation.
+func (s *String) RotateLetters(rx, ry, rz float64) {
+	for _, pl := range s.Letters {
+		pl.Rotate(rx, ry, rz)
+	}
+}
+
+// ordered returns the letters in the order they are laid out, left to right.
+func (s *String) ordered() []*Shape {
+	if !s.RightToLeft {
+		return s.Letters
+	}
+	letters := slices.Clone(s.Letters)
+	slices.Reverse(letters)
+	return letters
+}
+
 //////////////////////////////
 // Font definitions
 //////////////////////////////
 
 // FontType is a struct holding the font data.
+// Glyph coordinates are single hex digits for x and y. x runs from 0 to width. y runs from 0 at the bottom
+// of the descenders, through descent at the baseline, to descent + height at the top of the capitals.
 type FontType = struct {
-	data   map[string]string
-	width  float64
-	height float64
+	data    map[string]string
+	width   float64
+	height  float64
+	descent float64
 }
 
 // FontArcade is the path data for this font.
@@ -136,78 +297,113 @@ type FontType = struct {
 // with some changes.
 var FontArcade = FontType{
 	data: map[string]string{
-		" ":  "00",
-		"!":  "00 01 11 10 00 :  03 08 18 13 03",
-		"#":  "03 83 :  05 85 :  30 38 :  50 58",
-		"$":  "01 81 84 04 07 87 :  40 48",
-		"%":  "00 88 :  18 28 27 17 18 :  70 71 61 60 70",
-		"&":  "80 47 58 67 21 30 60 82",
-		"'":  "07 08 18 17 07 :  17 05",
-		"(":  "28 04 20",
-		")":  "08 24 00",
-		"*":  "41 47 :  14 74 :  22 66 :  26 62",
-		"+":  "41 47 :  14 74",
-		",":  "01 02 12 11 01 :  11 00",
-		"-":  "14 74",
-		".":  "00 01 11 10 00",
-		"/":  "00 88",
-		"0":  "00 80 88 08 00",
-		"1":  "00 80 :  40 48 26",
-		"2":  "08 88 84 04 00 80",
-		"3":  "08 88 80 00 :  04 84",
-		"4":  "08 04 84 :  88 80",
-		"5":  "00 80 84 04 08 88",
-		"6":  "08 00 80 84 04",
-		"7":  "08 88 80",
-		"8":  "00 08 88 80 00 :  04 84",
-		"9":  "80 88 08 04 84",
-		":":  "02 03 13 12 02 :  05 06 16 15 05",
-		";":  "02 03 13 12 02 :  05 06 16 15 05 :  1 2 0 0",
-		"<":  "87 04 81",
-		"=":  "13 73 :  15 75",
-		">":  "07 84 01",
-		"?":  "06 08 88 84 44 40",
-		"@":  "71 60 20 02 06 28 68 86 84 62 22 24 36 66 62",
-		"A":  "00 06 48 86 80 :  03 83",
-		"B":  "00 08 58 76 54 04 :  64 82 60 00",
-		"C":  "88 08 00 80",
-		"D":  "00 08 58 85 83 50 00",
-		"E":  "88 08 00 80 :  04 64",
-		"F":  "88 08 00 :  04 64",
-		"G":  "88 08 00 80 83 43",
-		"H":  "00 08 :  80 88 :  04 84",
-		"I":  "00 80 :  08 88 :  40 48",
-		"J":  "88 80 40 03",
-		"K":  "00 08 :  88 04 80",
-		"L":  "08 00 80",
-		"M":  "00 08 45 88 80",
-		"N":  "00 08 80 88",
-		"O":  "00 80 88 08 00",
-		"P":  "00 08 88 84 04",
-		"Q":  "00 08 88 83 40 00 :  43 80",
-		"R":  "00 08 88 84 04 80",
-		"S":  "00 80 84 04 08 88",
-		"T":  "08 88 :  40 48",
-		"U":  "08 00 80 88",
-		"V":  "08 40 88",
-		"W":  "08 00 43 80 88",
-		"X":  "00 88 :  08 80",
-		"Y":  "08 45 88 :  45 40",
-		"Z":  "08 88 00 80",
-		"[":  "28 08 00 20",
-		"\"": "07 08 18 17 07 :  17 05 :  27 28 38 37 27 :  37 25",
-		"\\": "08 80",
-		"]":  "08 28 20 00",
-		"^":  "26 48 66",
-		"_":  "00 80",
-		"`":  "18 26",
-		"{":  "28 04 20",
-		"|":  "40 48",
-		"}":  "08 24 00",
-		"~":  "04 26 64 86",
+		" ":  "02",
+		"!":  "02 03 13 12 02 :  05 0A 1A 15 05",
+		"#":  "05 85 :  07 87 :  32 3A :  52 5A",
+		"$":  "03 83 86 06 09 89 :  42 4A",
+		"%":  "02 8A :  1A 2A 29 19 1A :  72 73 63 62 72",
+		"&":  "82 49 5A 69 23 32 62 84",
+		"'":  "09 0A 1A 19 09 :  19 07",
+		"(":  "2A 06 22",
+		")":  "0A 26 02",
+		"*":  "43 49 :  16 76 :  24 68 :  28 64",
+		"+":  "43 49 :  16 76",
+		",":  "03 04 14 13 03 :  13 02",
+		"-":  "16 76",
+		".":  "02 03 13 12 02",
+		"/":  "02 8A",
+		"0":  "02 82 8A 0A 02",
+		"1":  "02 82 :  42 4A 28",
+		"2":  "0A 8A 86 06 02 82",
+		"3":  "0A 8A 82 02 :  06 86",
+		"4":  "0A 06 86 :  8A 82",
+		"5":  "02 82 86 06 0A 8A",
+		"6":  "0A 02 82 86 06",
+		"7":  "0A 8A 82",
+		"8":  "02 0A 8A 82 02 :  06 86",
+		"9":  "82 8A 0A 06 86",
+		":":  "04 05 15 14 04 :  07 08 18 17 07",
+		";":  "04 05 15 14 04 :  07 08 18 17 07 :  14 02",
+		"<":  "89 06 83",
+		"=":  "15 75 :  17 77",
+		">":  "09 86 03",
+		"?":  "08 0A 8A 86 46 42",
+		"@":  "73 62 22 04 08 2A 6A 88 86 64 24 26 38 68 64",
+		"A":  "02 08 4A 88 82 :  05 85",
+		"B":  "02 0A 5A 78 56 06 :  66 84 62 02",
+		"C":  "8A 0A 02 82",
+		"D":  "02 0A 5A 87 85 52 02",
+		"E":  "8A 0A 02 82 :  06 66",
+		"F":  "8A 0A 02 :  06 66",
+		"G":  "8A 0A 02 82 85 45",
+		"H":  "02 0A :  82 8A :  06 86",
+		"I":  "02 82 :  0A 8A :  42 4A",
+		"J":  "8A 82 42 05",
+		"K":  "02 0A :  8A 06 82",
+		"L":  "0A 02 82",
+		"M":  "02 0A 47 8A 82",
+		"N":  "02 0A 82 8A",
+		"O":  "02 82 8A 0A 02",
+		"P":  "02 0A 8A 86 06",
+		"Q":  "02 0A 8A 85 42 02 :  45 82",
+		"R":  "02 0A 8A 86 06 82",
+		"S":  "02 82 86 06 0A 8A",
+		"T":  "0A 8A :  42 4A",
+		"U":  "0A 02 82 8A",
+		"V":  "0A 42 8A",
+		"W":  "0A 02 45 82 8A",
+		"X":  "02 8A :  0A 82",
+		"Y":  "0A 47 8A :  47 42",
+		"Z":  "0A 8A 02 82",
+		"[":  "2A 0A 02 22",
+		"\"": "09 0A 1A 19 09 :  19 07 :  29 2A 3A 39 29 :  39 27",
+		"\\": "0A 82",
+		"]":  "0A 2A 22 02",
+		"^":  "28 4A 68",
+		"_":  "02 82",
+		"`":  "1A 28",
+		"a":  "07 87 82 02 05 85",
+		"b":  "0A 02 82 87 07",
+		"c":  "87 07 02 82",
+		"d":  "8A 82 02 07 87",
+		"e":  "05 85 87 07 02 82",
+		"f":  "7A 3A 32 :  07 67",
+		"g":  "82 02 07 87 80 00",
+		"h":  "0A 02 :  07 87 82",
+		"i":  "42 47 :  49 4A",
+		"j":  "00 60 67 :  69 6A",
+		"k":  "0A 02 :  87 04 82",
+		"l":  "2A 4A 42",
+		"m":  "02 07 87 82 :  47 42",
+		"n":  "02 07 87 82",
+		"o":  "02 07 87 82 02",
+		"p":  "00 07 87 82 02",
+		"q":  "80 87 07 02 82",
+		"r":  "02 07 :  05 27 87",
+		"s":  "87 07 05 85 82 02",
+		"t":  "3A 32 82 :  07 77",
+		"u":  "07 02 82 87",
+		"v":  "07 42 87",
+		"w":  "07 02 82 87 :  42 45",
+		"x":  "02 87 :  07 82",
+		"y":  "07 02 82 :  87 80 00",
+		"z":  "07 87 02 82",
+		"{":  "2A 06 22",
+		"|":  "42 4A",
+		"}":  "0A 26 02",
+		"~":  "06 28 66 88",
+		"°":  "0A 3A 37 07 0A",
+		"±":  "49 45 :  17 77 :  13 73",
+		"×":  "13 79 :  19 73",
+		"÷":  "16 76 :  38 39 49 48 38 :  33 34 44 43 33",
+		"·":  "35 36 46 45 35",
+		"¢":  "89 09 03 83 :  4A 42",
+		"£":  "8A 3A 32 82 :  06 66",
+		"€":  "8A 2A 22 82 :  07 67 :  05 65",
 	},
-	width:  8,
-	height: 8,
+	width:   8,
+	height:  8,
+	descent: 2,
 }
 
 // FontAsteroid is the path data for this font.
@@ -215,76 +411,111 @@ var FontArcade = FontType{
 // with some changes.
 var FontAsteroid = FontType{
 	data: map[string]string{
-		" ":  "00",
-		"!":  "40 32 52 40 : 44 4C",
-		"#":  "04 84 62 6A 88 08 2A 22",
-		"$":  "62 26 6A : 4C 40",
-		"%":  "00 8C : 2A 28 : 64 62",
-		"&":  "80 4C 88 04 40 84",
-		"'":  "26 6A",
-		"(":  "60 24 28 6C",
-		")":  "20 64 68 2C",
-		"*":  "00 4C 80 08 88 00",
-		"+":  "16 76 : 49 43",
-		",":  "30 42",
-		"-":  "26 66",
-		".":  "30 40",
-		"/":  "00 8C",
-		"0":  "00 80 8C 0C 00 8C",
-		"1":  "40 4C 3A",
-		"2":  "0C 8C 87 05 00 80",
-		"3":  "0C 8C 80 00 : 06 86",
-		"4":  "0C 06 86 : 8C 80",
-		"5":  "00 80 86 07 0C 8C",
-		"6":  "0C 00 80 85 07",
-		"7":  "0C 8C 86 40",
-		"8":  "00 80 8C 0C 00 : 06 86",
-		"9":  "80 8C 0C 07 85",
-		":":  "49 47 : 45 43",
-		";":  "49 47 : 45 12",
-		"<":  "60 26 6C",
-		"=":  "14 74 : 18 78",
-		">":  "20 66 2C",
-		"?":  "08 4C 88 44 : 41 40",
-		"@":  "84 40 04 08 4C 88 44 36",
-		"A":  "00 08 4C 88 80 : 04 84",
-		"B":  "00 0C 4C 8A 46 82 40 00",
-		"C":  "80 00 0C 8C",
-		"D":  "00 0C 4C 88 84 40 00",
-		"E":  "80 00 0C 8C : 06 66",
-		"F":  "00 0C 8C : 06 66",
-		"G":  "66 84 80 00 0C 8C",
-		"H":  "00 0C : 06 86 : 8C 80",
-		"I":  "00 80 : 40 4C : 0C 8C",
-		"J":  "04 40 80 8C",
-		"K":  "00 0C : 8C 06 60",
-		"L":  "80 00 0C",
-		"M":  "00 0C 48 8C 80",
-		"N":  "00 0C 80 8C",
-		"O":  "00 0C 8C 80 00",
-		"P":  "00 0C 8C 86 05",
-		"Q":  "00 0C 8C 84 00 : 44 80",
-		"R":  "00 0C 8C 86 05 : 45 80",
-		"S":  "02 20 80 85 07 0C 6C 8A",
-		"T":  "0C 8C : 4C 40",
-		"U":  "0C 02 40 82 8C",
-		"V":  "0C 40 8C",
-		"W":  "0C 20 44 60 8C",
-		"X":  "00 8C : 0C 80",
-		"Y":  "0C 46 8C : 46 40",
-		"Z":  "0C 8C 00 80 : 26 66",
-		"[":  "60 20 2C 6C",
-		"\"": "2A 26 : 6A 66",
-		"\\": "0C 80",
-		"]":  "20 60 6C 2C",
-		"^":  "26 4C 66",
-		"_":  "00 80",
-		"`":  "2A 66",
-		"{":  "60 42 4A 6C : 26 46",
-		"|":  "40 45 : 46 4C",
-		"}":  "40 62 6A 4C : 66 86",
-		"~":  "04 28 64 88",
+		" ":  "03",
+		"!":  "43 35 55 43 : 47 4F",
+		"#":  "07 87 65 6D 8B 0B 2D 25",
+		"$":  "65 29 6D : 4F 43",
+		"%":  "03 8F : 2D 2B : 67 65",
+		"&":  "83 4F 8B 07 43 87",
+		"'":  "29 6D",
+		"(":  "63 27 2B 6F",
+		")":  "23 67 6B 2F",
+		"*":  "03 4F 83 0B 8B 03",
+		"+":  "19 79 : 4C 46",
+		",":  "33 45",
+		"-":  "29 69",
+		".":  "33 43",
+		"/":  "03 8F",
+		"0":  "03 83 8F 0F 03 8F",
+		"1":  "43 4F 3D",
+		"2":  "0F 8F 8A 08 03 83",
+		"3":  "0F 8F 83 03 : 09 89",
+		"4":  "0F 09 89 : 8F 83",
+		"5":  "03 83 89 0A 0F 8F",
+		"6":  "0F 03 83 88 0A",
+		"7":  "0F 8F 89 43",
+		"8":  "03 83 8F 0F 03 : 09 89",
+		"9":  "83 8F 0F 0A 88",
+		":":  "4C 4A : 48 46",
+		";":  "4C 4A : 48 15",
+		"<":  "63 29 6F",
+		"=":  "17 77 : 1B 7B",
+		">":  "23 69 2F",
+		"?":  "0B 4F 8B 47 : 44 43",
+		"@":  "87 43 07 0B 4F 8B 47 39",
+		"A":  "03 0B 4F 8B 83 : 07 87",
+		"B":  "03 0F 4F 8D 49 85 43 03",
+		"C":  "83 03 0F 8F",
+		"D":  "03 0F 4F 8B 87 43 03",
+		"E":  "83 03 0F 8F : 09 69",
+		"F":  "03 0F 8F : 09 69",
+		"G":  "69 87 83 03 0F 8F",
+		"H":  "03 0F : 09 89 : 8F 83",
+		"I":  "03 83 : 43 4F : 0F 8F",
+		"J":  "07 43 83 8F",
+		"K":  "03 0F : 8F 09 63",
+		"L":  "83 03 0F",
+		"M":  "03 0F 4B 8F 83",
+		"N":  "03 0F 83 8F",
+		"O":  "03 0F 8F 83 03",
+		"P":  "03 0F 8F 89 08",
+		"Q":  "03 0F 8F 87 03 : 47 83",
+		"R":  "03 0F 8F 89 08 : 48 83",
+		"S":  "05 23 83 88 0A 0F 6F 8D",
+		"T":  "0F 8F : 4F 43",
+		"U":  "0F 05 43 85 8F",
+		"V":  "0F 43 8F",
+		"W":  "0F 23 47 63 8F",
+		"X":  "03 8F : 0F 83",
+		"Y":  "0F 49 8F : 49 43",
+		"Z":  "0F 8F 03 83 : 29 69",
+		"[":  "63 23 2F 6F",
+		"\"": "2D 29 : 6D 69",
+		"\\": "0F 83",
+		"]":  "23 63 6F 2F",
+		"^":  "29 4F 69",
+		"_":  "03 83",
+		"`":  "2D 69",
+		"a":  "1B 8B 83 03 07 87",
+		"b":  "0F 03 63 85 89 6B 0B",
+		"c":  "8B 0B 03 83",
+		"d":  "8F 83 23 05 09 2B 8B",
+		"e":  "07 87 8B 0B 03 83",
+		"f":  "8F 4F 2D 23 : 0B 6B",
+		"g":  "8B 2B 09 05 23 83 : 8B 81 60 00",
+		"h":  "0F 03 : 09 2B 8B 83",
+		"i":  "43 4B : 4D 4E",
+		"j":  "6B 61 40 00 : 6D 6E",
+		"k":  "0F 03 : 8B 07 83",
+		"l":  "4F 43",
+		"m":  "03 0B : 09 2B 49 43 : 49 6B 89 83",
+		"n":  "03 0B : 09 2B 8B 83",
+		"o":  "03 0B 8B 83 03",
+		"p":  "00 0B 6B 89 85 63 03",
+		"q":  "80 8B 2B 09 05 23 83",
+		"r":  "03 0B : 08 3B 8B",
+		"s":  "8B 0B 07 87 83 03",
+		"t":  "3F 33 83 : 0B 7B",
+		"u":  "0B 03 83 8B",
+		"v":  "0B 43 8B",
+		"w":  "0B 23 47 63 8B",
+		"x":  "03 8B : 0B 83",
+		"y":  "0B 43 : 8B 20",
+		"z":  "0B 8B 03 83",
+		"{":  "63 45 4D 6F : 29 49",
+		"|":  "43 48 : 49 4F",
+		"}":  "43 65 6D 4F : 69 89",
+		"~":  "07 2B 67 8B",
+		"°":  "2F 5F 5C 2C 2F",
+		"±":  "4D 47 : 1A 7A : 15 75",
+		"×":  "16 7C : 1C 76",
+		"÷":  "19 79 : 4C 4D : 45 46",
+		"·":  "39 49",
+		"¢":  "8D 0D 05 85 : 4F 43",
+		"£":  "8F 4F 2D 23 83 : 09 69",
+		"€":  "8F 2F 23 83 : 0B 6B : 07 67",
 	},
-	width:  8,
-	height: 12,
+	width:   8,
+	height:  12,
+	descent: 3,
 }
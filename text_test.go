@@ -0,0 +1,111 @@
+package wire
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var updateGlyphs = flag.Bool("update", false, "rewrite testdata/glyphs.txt from the built in fonts")
+
+const glyphsFile = "testdata/glyphs.txt"
+
+// glyphRecord returns a reference line for one glyph: its font, character, segment count and a hash of its data.
+func glyphRecord(fontName, char, data string) string {
+	segments := 0
+	for _, stroke := range strings.Split(data, ":") {
+		segments += max(0, len(strings.Fields(stroke))-1)
+	}
+	sum := sha256.Sum256([]byte(data))
+	return fmt.Sprintf("%s %q %d %s", fontName, char, segments, hex.EncodeToString(sum[:8]))
+}
+
+// glyphRecords returns the reference lines for every glyph of every built in font, in order.
+func glyphRecords() []string {
+	records := []string{}
+	for _, f := range builtinFonts() {
+		chars := []string{}
+		for char := range f.font.data {
+			chars = append(chars, char)
+		}
+		sort.Strings(chars)
+		for _, char := range chars {
+			records = append(records, glyphRecord(f.name, char, f.font.data[char]))
+		}
+	}
+	return records
+}
+
+func TestValidateFonts(t *testing.T) {
+	if err := ValidateFonts(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGlyphsMatchReference(t *testing.T) {
+	got := glyphRecords()
+	if *updateGlyphs {
+		if err := os.WriteFile(glyphsFile, []byte(strings.Join(got, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file, err := os.Open(glyphsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	want := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		want = append(want, scanner.Text())
+	}
+	if len(got) != len(want) {
+		t.Errorf("found %d glyphs, reference has %d", len(got), len(want))
+	}
+	wanted := map[string]bool{}
+	for _, line := range want {
+		wanted[line] = true
+	}
+	for _, line := range got {
+		if !wanted[line] {
+			t.Errorf("glyph does not match reference: %s", line)
+		}
+	}
+}
+
+func TestValidateFontBroken(t *testing.T) {
+	broken := FontType{
+		data: map[string]string{
+			"A": "00 84 08",
+			"B": "00 0G",
+			"C": "00 00 80",
+			"D": "00 F0",
+			"E": "00 80 : ",
+		},
+		width:  8,
+		height: 8,
+	}
+	err := ValidateFont(broken)
+	if err == nil {
+		t.Fatal("broken font passed validation")
+	}
+	for _, problem := range []string{
+		`"B": bad coordinate "0G"`,
+		`"C": zero length segment at "00"`,
+		`"D": coordinate "F0" out of range`,
+		`"E": empty stroke`,
+	} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("error %q does not report %s", err, problem)
+		}
+	}
+	if strings.Contains(err.Error(), `"A"`) {
+		t.Errorf("error %q reports the valid glyph", err)
+	}
+}
@@ -0,0 +1,132 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"math"
+	"strings"
+)
+
+// TextTicker is a line of text that scrolls endlessly through a window, like a news ticker or marquee.
+// Create one with Ticker, then call Line or Band each frame with the current time.
+type TextTicker struct {
+	// Width is the width of the window the text scrolls through, or the length of the band around its cylinder.
+	Width float64
+	// Speed is how far the text moves per unit of time. Positive speeds scroll to the left, negative to the right.
+	Speed float64
+	// Gap is the space between the end of the text and the start of its next repeat. Defaults to twice the font size.
+	Gap     float64
+	letters []*Shape
+	advance float64
+}
+
+// Ticker creates a ticker for the given text, scrolling through a window of the given width at the given speed.
+// The text uses the current font, font size and font spacing, which are fixed when the ticker is created.
+func Ticker(text string, width float64, speed float64) *TextTicker {
+	letters := []*Shape{}
+	for _, r := range text {
+		c := string(r)
+		if _, ok := world.Font.data[c]; !ok {
+			c = strings.ToUpper(c)
+		}
+		letters = append(letters, ParseChar(c, world.Font))
+	}
+	return &TextTicker{
+		Width:   width,
+		Speed:   speed,
+		Gap:     world.FontSize * 2,
+		letters: letters,
+		advance: world.FontSize * (1 + world.FontSpacing),
+	}
+}
+
+// period returns the distance between the starts of each repeat of the text.
+func (t *TextTicker) period() float64 {
+	return float64(len(t.letters))*t.advance + t.Gap
+}
+
+// place calls f with a copy of each letter that should be drawn at time tm, and the x position of its center
+// in the window, which runs from -Width/2 to Width/2.
+func (t *TextTicker) place(tm, period float64, f func(letter *Shape, x float64)) {
+	offset := math.Mod(t.Speed*tm, period)
+	if offset < 0 {
+		offset += period
+	}
+	half := world.FontSize / 2
+	for start := -t.Width/2 - offset; start < t.Width/2+half; start += period {
+		for i, letter := range t.letters {
+			f(letter, start+half+t.advance*float64(i))
+		}
+	}
+}
+
+// Line returns the ticker's text at time tm, laid out in a horizontal line through a window Width wide, centered on the origin.
+// The text repeats, separated by Gap, so there is never a jump as it wraps around.
+// Letters are cut off cleanly where they cross the edges of the window.
+func (t *TextTicker) Line(tm float64) *Shape {
+	shape := NewShape()
+	if len(t.letters) == 0 {
+		return shape
+	}
+	half := world.FontSize / 2
+	left, right := -t.Width/2, t.Width/2
+	t.place(tm, t.period(), func(letter *Shape, x float64) {
+		if x+half <= left || x-half >= right {
+			return
+		}
+		for _, seg := range letter.Segments {
+			a, b := seg.PointA.Clone(), seg.PointB.Clone()
+			a.TranslateX(x)
+			b.TranslateX(x)
+			a, b, ok := clipSegmentX(a, b, left, right)
+			if !ok {
+				continue
+			}
+			shape.AddPoint(a)
+			shape.AddPoint(b)
+			shape.AddSegmentByPoints(a, b)
+		}
+	})
+	return shape
+}
+
+// Band returns the ticker's text at time tm, wrapped all the way around a vertical cylinder whose circumference is Width,
+// centered on the origin, with the letters facing out. The gap between repeats is stretched a little if needed,
+// so that a whole number of repeats fit around the band and it scrolls seamlessly.
+func (t *TextTicker) Band(tm float64) *Shape {
+	shape := NewShape()
+	if len(t.letters) == 0 {
+		return shape
+	}
+	period := t.period()
+	period = t.Width / math.Max(1, math.Floor(t.Width/period))
+	radius := t.Width / (2 * math.Pi)
+	t.place(tm, period, func(letter *Shape, x float64) {
+		// each spot on the band is drawn once, even though the repeats overlap the ends of the window.
+		if x < -t.Width/2 || x >= t.Width/2 {
+			return
+		}
+		l := letter.Clone()
+		l.TranslateZ(-radius)
+		l.RotateY(-x / radius)
+		shape.AddShape(l)
+	})
+	return shape
+}
+
+// clipSegmentX clips the segment from a to b to the x range from minX to maxX, moving the points in place.
+// Returns false if none of the segment is in the range.
+func clipSegmentX(a, b *Point, minX, maxX float64) (*Point, *Point, bool) {
+	if a.X > b.X {
+		a, b = b, a
+	}
+	if b.X < minX || a.X > maxX {
+		return a, b, false
+	}
+	if a.X < minX {
+		a.Lerp((minX-a.X)/(b.X-a.X), b)
+	}
+	if b.X > maxX {
+		b.Lerp((b.X-maxX)/(b.X-a.X), a)
+	}
+	return a, b, true
+}
@@ -0,0 +1,52 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Timecode creates a shape showing the timecode of the given frame, as hours:minutes:seconds:frames,
+// in the current font, laid out as a line centered on the origin. fps is rounded to a whole number of frames
+// per second, so rates like 29.97 give non drop frame timecode. Use PinToCorner to show it over a render.
+func Timecode(frame int, fps float64) *Shape {
+	rate := max(1, int(math.Round(fps)))
+	frame = max(0, frame)
+	secs := frame / rate
+	code := fmt.Sprintf("%02d:%02d:%02d:%02d", secs/3600, secs/60%60, secs%60, frame%rate)
+	return NewString(code).AsLine()
+}
+
+// Countdown creates a shape showing a film leader style countdown from the given number of seconds at time t:
+// the number of whole seconds left, in the current font, inside a circle with a crosshair and a hand
+// that sweeps once around the circle each second. It is centered on the origin. Once the countdown has run out
+// it shows 0 with the hand at the top.
+func Countdown(seconds float64, t float64) *Shape {
+	remaining := math.Max(0, seconds-t)
+	n := math.Ceil(remaining)
+	shape := NewString(strconv.Itoa(int(n))).AsLine()
+	b := shape.GetBounds()
+	// the number is centered in the circle, which leaves room for it.
+	shape.TranslateY(-(b.MinY + b.MaxY) / 2)
+	radius := math.Max(b.MaxX-b.MinX, b.MaxY-b.MinY) * 0.8
+
+	for _, r := range []float64{radius, radius * 1.1} {
+		circle := Circle(r, 64)
+		circle.RotateX(math.Pi / 2)
+		shape.AddShape(circle)
+	}
+	line := func(x0, y0, x1, y1 float64) {
+		a, b := NewPoint(x0, y0, 0), NewPoint(x1, y1, 0)
+		shape.AddPoint(a)
+		shape.AddPoint(b)
+		shape.AddSegmentByPoints(a, b)
+	}
+	line(-radius*1.2, 0, radius*1.2, 0)
+	line(0, -radius*1.2, 0, radius*1.2)
+
+	// the hand starts at the top and turns clockwise through the current second.
+	angle := (n - remaining) * 2 * math.Pi
+	line(0, 0, math.Sin(angle)*radius, -math.Cos(angle)*radius)
+	return shape
+}
@@ -0,0 +1,121 @@
+// Package wire implements wireframe 3d shapes.
+package wire
+
+//////////////////////////////////////////////////////////////
+// Incremental transforms.
+//
+// Normally, each transform on a shape moves all of its points.
+// Over a long animation, small floating point errors from many
+// repeated rotations add up and the shape slowly distorts.
+//
+// After calling KeepOriginal, a shape keeps a pristine copy of
+// its coordinates. Translations, rotations and scales then only
+// update an accumulated transform, which is applied to the
+// original coordinates when the shape is rendered. Calling
+// ResetTransform each frame and transforming by absolute amounts
+// gives drift free animation without cloning the shape.
+//
+// DeferTransforms is lighter: no original is kept, but transforms
+// still only update the accumulated transform. It is applied to
+// the points in a single pass when the shape is rendered, then
+// reset. For shapes with many points, this saves a full pass over
+// the points for every transform call in a frame.
+//
+// The accumulated transform is stored as a frame of four points:
+// the transformed origin and the transformed ends of the three
+// unit axes. Transforming the frame with the regular PointList
+// methods is the same as composing a matrix.
+//////////////////////////////////////////////////////////////
+
+// KeepOriginal stores a copy of the shape's current coordinates and starts accumulating translations,
+// rotations and scales, rather than applying them to the points directly. The shape's points are updated
+// from the originals when the shape is rendered, or when ApplyTransform is called.
+// Other modifications, such as Randomize, Twist or Wrap, still act on the points directly and will be
+// overwritten on the next update, so they should be done before calling KeepOriginal.
+func (s *Shape) KeepOriginal() {
+	s.ApplyTransform()
+	s.original = s.Points.Clone()
+	s.resetFrame()
+}
+
+// DeferTransforms turns deferred transforms on or off. While on, translations, rotations, scales, shears
+// and other matrix transforms are accumulated rather than applied to the points directly.
+// The accumulated transform is applied to the points in a single pass when the shape is rendered,
+// or when ApplyTransform is called, then reset. Shape methods that work on the points directly,
+// such as Randomize, Twist or GetSize, apply it first. PointList methods called on the shape's Points
+// bypass it, so call ApplyTransform before using them, or before reading or changing the points yourself. Turning deferred transforms off applies any pending transform.
+// Has no effect if KeepOriginal has been called.
+func (s *Shape) DeferTransforms(on bool) {
+	if s.original != nil {
+		return
+	}
+	if on {
+		if s.frame == nil {
+			s.resetFrame()
+		}
+		return
+	}
+	s.ApplyTransform()
+	s.frame = nil
+}
+
+// ReleaseOriginal applies the accumulated transform to the shape's points, then discards the original
+// coordinates. Transforms will once again be applied directly to the points.
+func (s *Shape) ReleaseOriginal() {
+	s.ApplyTransform()
+	s.original = nil
+	s.frame = nil
+}
+
+// ResetTransform resets the accumulated transform, returning the shape to its original coordinates
+// on the next update. With deferred transforms, any pending transform is discarded.
+// Has no effect unless KeepOriginal or DeferTransforms has been called.
+func (s *Shape) ResetTransform() {
+	if s.frame == nil {
+		return
+	}
+	s.resetFrame()
+}
+
+// resetFrame sets the accumulated transform to one that does not move any points.
+func (s *Shape) resetFrame() {
+	s.frame = PointList{
+		NewPoint(0, 0, 0),
+		NewPoint(1, 0, 0),
+		NewPoint(0, 1, 0),
+		NewPoint(0, 0, 1),
+	}
+}
+
+// ApplyTransform updates the shape's points by applying the accumulated transform to the original coordinates,
+// or with deferred transforms, applies the pending transform to the points and resets it.
+// This is called automatically when rendering, but can be called to make the points current before reading them.
+// Has no effect unless KeepOriginal or DeferTransforms has been called.
+func (s *Shape) ApplyTransform() {
+	if s.frame == nil {
+		return
+	}
+	m := frameMatrix(s.frame)
+	if s.original == nil {
+		if m != IdentityMatrix() {
+			s.Points.Transform(m)
+			s.resetFrame()
+		}
+		return
+	}
+	count := min(len(s.original), len(s.Points))
+	for i, p := range s.original[:count] {
+		dst := s.Points[i]
+		dst.X, dst.Y, dst.Z = p.X, p.Y, p.Z
+		dst.Transform(m)
+	}
+}
+
+// transformTarget returns the points that translations, rotations and scales should act on:
+// the accumulated transform frame if KeepOriginal or DeferTransforms has been called, otherwise the shape's points.
+func (s *Shape) transformTarget() PointList {
+	if s.frame != nil {
+		return s.frame
+	}
+	return s.Points
+}
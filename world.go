@@ -3,6 +3,9 @@ package wire
 
 import (
 	"math"
+	"runtime"
+	"slices"
+	"sync"
 
 	"github.com/bit101/bitlib/blcolor"
 	"github.com/bit101/bitlib/blmath"
@@ -28,9 +31,47 @@ type Context interface {
 	FillTextAny(text any, x, y float64)
 }
 
+// Blurrer is implemented by contexts that can blur what has been drawn, such as cairo.Context.
+// StrokeGlow only blurs if the world's Context is a Blurrer.
+type Blurrer interface {
+	GaussianBlur(float64)
+}
+
+// Filler is implemented by contexts that can fill paths, such as cairo.Context.
+// Faces are only filled if the world's Context is a Filler.
+type Filler interface {
+	Fill()
+}
+
+// WidthSpace determines how stroke widths and point radii are measured.
+type WidthSpace int
+
+const (
+	// WorldSpace sizes are in world units, so lines and points get thinner as they recede,
+	// and scale with changes in perspective.
+	WorldSpace WidthSpace = iota
+	// ScreenSpace sizes are in pixels, so lines and points are the same size at any depth.
+	ScreenSpace
+)
+
+// FogCurveType determines how fog thickens between its near and far distances.
+type FogCurveType int
+
+const (
+	// FogLinear fades objects evenly with distance.
+	FogLinear FogCurveType = iota
+	// FogExp fades objects quickly at first, then more slowly, like real world fog.
+	FogExp
+	// FogExp2 keeps nearby objects clear, then fades them quickly.
+	FogExp2
+	// FogSmoothstep eases in and out of the fog.
+	FogSmoothstep
+)
+
 type worldDef struct {
 	FL               float64
 	CX, CY, CZ       float64
+	Width, Height    float64
 	NearZ, FarZ      float64
 	FogActive        bool
 	NearFog          float64
@@ -44,6 +85,25 @@ type worldDef struct {
 	FontSize         float64
 	FontSpacing      float64
 	LabelPoints      bool
+	DensityActive    bool
+	DensityRadius    float64
+	DensityCount     float64
+	Time             float64
+	BoilAmplitude    float64
+	BoilFPS          float64
+	Workers          int
+	WidthSpace       WidthSpace
+	FogCurve         FogCurveType
+	FogLayers        []*FogLayer
+	MaxStrokeLength  float64
+	MaxStrokeDepth   float64
+	DepthSort        bool
+	BackfaceCulling  bool
+	PixelSnap        bool
+	Supersample      float64
+	Occluders        []occluder
+	DepthWidthFunc   func(z float64) float64
+	DepthAlphaFunc   func(z float64) float64
 }
 
 // World contains the parameters for the 3d world.
@@ -52,6 +112,8 @@ var world = worldDef{
 	CX:               0.0,
 	CY:               0.0,
 	CZ:               0.0,
+	Width:            0.0,
+	Height:           0.0,
 	NearZ:            100.0,
 	FarZ:             100000.0,
 	FogActive:        false,
@@ -68,6 +130,25 @@ var world = worldDef{
 	FontSize:         100,
 	FontSpacing:      0.2,
 	LabelPoints:      false,
+	DensityActive:    false,
+	DensityRadius:    10.0,
+	DensityCount:     8.0,
+	Time:             0.0,
+	BoilAmplitude:    0.0,
+	BoilFPS:          12.0,
+	Workers:          1,
+	WidthSpace:       WorldSpace,
+	FogCurve:         FogLinear,
+	FogLayers:        nil,
+	MaxStrokeLength:  0.0,
+	MaxStrokeDepth:   0.0,
+	DepthSort:        false,
+	BackfaceCulling:  false,
+	PixelSnap:        false,
+	Supersample:      1.0,
+	Occluders:        nil,
+	DepthWidthFunc:   nil,
+	DepthAlphaFunc:   nil,
 }
 
 // InitWorld initializes the world.
@@ -89,39 +170,140 @@ func SetRGB(r, g, b float64) {
 	world.B = b
 }
 
-// SetPerspective sets the amount of perspective to apply.
+// SetPerspective sets the amount of perspective to apply. See SetFOV to set it as a field of view.
 func SetPerspective(fl float64) {
 	world.FL = fl
 }
 
+// SetFOV sets the perspective from a vertical field of view, in degrees, from the top of the canvas to the bottom.
+// The canvas height is the one given to InitWorldForCanvas, or twice the world's y center otherwise.
+// Has no effect if neither is set. Wider angles give stronger perspective.
+// See DollyZoom to animate it without the subject changing size.
+func SetFOV(degrees float64) {
+	height := canvasHeight()
+	if height <= 0 {
+		return
+	}
+	world.FL = fovToFL(degrees, height)
+}
+
+// GetFOV returns the vertical field of view, in degrees, for the current perspective and canvas. See SetFOV.
+func GetFOV() float64 {
+	return 2 * math.Atan2(canvasHeight()/2, world.FL) * 180 / math.Pi
+}
+
+// canvasHeight returns the height of the canvas set by InitWorldForCanvas, or twice the world's y center otherwise.
+func canvasHeight() float64 {
+	_, height := canvasSize()
+	return height
+}
+
+// canvasSize returns the size of the canvas set by InitWorldForCanvas, or twice the world's center otherwise.
+func canvasSize() (float64, float64) {
+	if world.Width > 0 && world.Height > 0 {
+		return world.Width, world.Height
+	}
+	return world.CX * 2, world.CY * 2
+}
+
+// DollyZoom sets the field of view, as with SetFOV, and moves the camera in or out to match, so that anything
+// at the origin stays the same size on screen while the perspective around it changes, like the dolly zoom
+// camera move. Animating the field of view makes the background appear to stretch away or rush in behind the subject.
+// Has no effect if the canvas height is not known. See SetFOV.
+func DollyZoom(degrees float64) {
+	height := canvasHeight()
+	if height <= 0 {
+		return
+	}
+	fl := fovToFL(degrees, height)
+	world.CZ *= fl / world.FL
+	world.FL = fl
+}
+
 // SetCenter sets the center of the 3d world.
 func SetCenter(x, y, z float64) {
 	world.CX, world.CY, world.CZ = x, y, z
 }
 
 // SetClipping sets the near and far limits of rendering.
+// The near limit is kept slightly in front of the camera, as nothing at or behind it can be projected.
 func SetClipping(near, far float64) {
-	world.NearZ = near
+	world.NearZ = math.Max(near, minDepth)
 	world.FarZ = far
 }
 
 // ApplyFogAndWaterLevel sets the color to simulate an object receding into fog,
-// or being in water, or both.
+// or being in water, or both. Any fog layers the object is in are also applied.
 func ApplyFogAndWaterLevel(objectY, objectZ float64) {
+	shade(nil, objectY, objectZ, 1)
+}
+
+// shade sets the drawing color for an object at the given height and depth.
+// The object's color is the given color, or the world color if it is nil.
+// The color is tinted by any fog layers the object is in, and its alpha is multiplied
+// by the given alpha and the fog and water level visibility.
+// If there is nothing to change, the drawing color is left as is.
+func shade(color *blcolor.Color, objectY, objectZ, alpha float64) {
+	c := blcolor.RGB(world.R, world.G, world.B)
+	if color != nil {
+		c = *color
+	}
+	tinted := false
+	for _, layer := range world.FogLayers {
+		amount := layer.amount(objectY)
+		if amount > 0 {
+			c.R = blmath.Lerp(amount, c.R, layer.Color.R)
+			c.G = blmath.Lerp(amount, c.G, layer.Color.G)
+			c.B = blmath.Lerp(amount, c.B, layer.Color.B)
+			tinted = true
+		}
+	}
+	alpha *= FogAndWaterLevel(objectY, objectZ)
+	if world.DepthAlphaFunc != nil {
+		alpha *= blmath.Clamp(world.DepthAlphaFunc(objectZ+world.CZ), 0, 1)
+	}
+	if color == nil && !tinted && alpha >= 1 {
+		return
+	}
+	world.Context.SetSourceColor(blcolor.RGBA(c.R, c.G, c.B, c.A*alpha))
+}
+
+// FogAndWaterLevel returns the combined fog and water level visibility of an object,
+// from 0 (fully obscured) to 1 (fully visible).
+func FogAndWaterLevel(objectY, objectZ float64) float64 {
 	fog := 1.0
 	if world.FogActive {
-		fog = blmath.Map(objectZ+world.CZ, world.NearFog, world.FarFog, 1, 0)
+		fog = fogCurve(blmath.Map(objectZ+world.CZ, world.NearFog, world.FarFog, 0, 1))
 	}
 	if world.WaterLevelActive {
-		fog = math.Min(fog, blmath.Map(objectY, world.WaterLevelTop, world.WaterLevelBottom, 1, 0))
+		fog = math.Min(fog, fogCurve(blmath.Map(objectY, world.WaterLevelTop, world.WaterLevelBottom, 0, 1)))
 	}
-	fog = blmath.Clamp(fog, 0, 1)
-	if fog < 1 {
-		color := blcolor.RGBA(world.R, world.G, world.B, fog)
-		world.Context.SetSourceColor(color)
+	return blmath.Clamp(fog, 0, 1)
+}
+
+// fogCurve returns the visibility for a normalized fog distance t, using the world's fog curve.
+// t is clamped to 0 to 1. The result is 1 at t = 0 and 0 at t = 1.
+func fogCurve(t float64) float64 {
+	t = blmath.Clamp(t, 0, 1)
+	switch world.FogCurve {
+	case FogExp:
+		// normalized so the curve reaches zero at t = 1.
+		return (math.Exp(-4*t) - math.Exp(-4)) / (1 - math.Exp(-4))
+	case FogExp2:
+		return (math.Exp(-4*t*t) - math.Exp(-4)) / (1 - math.Exp(-4))
+	case FogSmoothstep:
+		return 1 - t*t*(3-2*t)
+	default:
+		return 1 - t
 	}
 }
 
+// SetFogCurve sets the curve used to fade objects between the near and far fog distances,
+// and between the top and bottom water levels. Default is FogLinear.
+func SetFogCurve(curve FogCurveType) {
+	world.FogCurve = curve
+}
+
 // SetWaterLevel sets the water level parameters, including turning on and off.
 // This is the same as fog but applied to the y axis.
 func SetWaterLevel(active bool, top, bottom float64) {
@@ -137,6 +319,243 @@ func SetFog(active bool, near, far float64) {
 	world.FarFog = far
 }
 
+// FogLayer is a band of fog between two heights on the y-axis, tinting objects by Density (0 to 1)
+// and fading out over Falloff units beyond Top and Bottom.
+type FogLayer struct {
+	Top, Bottom float64
+	Falloff     float64
+	Density     float64
+	Color       blcolor.Color
+}
+
+// NewFogLayer creates a new fog layer. top and bottom may be given in either order.
+func NewFogLayer(top, bottom, falloff, density float64, color blcolor.Color) *FogLayer {
+	return &FogLayer{
+		Top:     math.Max(top, bottom),
+		Bottom:  math.Min(top, bottom),
+		Falloff: falloff,
+		Density: density,
+		Color:   color,
+	}
+}
+
+// amount returns how strongly this layer tints an object at the given height, from 0 to 1.
+func (f *FogLayer) amount(y float64) float64 {
+	// distance outside the band. zero inside it.
+	d := math.Max(f.Bottom-y, y-f.Top)
+	if d <= 0 {
+		return blmath.Clamp(f.Density, 0, 1)
+	}
+	if d >= f.Falloff {
+		return 0
+	}
+	return blmath.Clamp(f.Density, 0, 1) * fogCurve(d/f.Falloff)
+}
+
+// AddFogLayer adds a fog layer to the world and returns it.
+// Layers are applied in the order they were added.
+func AddFogLayer(layer *FogLayer) *FogLayer {
+	world.FogLayers = append(world.FogLayers, layer)
+	return layer
+}
+
+// RemoveFogLayer removes a fog layer from the world.
+func RemoveFogLayer(layer *FogLayer) {
+	world.FogLayers = slices.DeleteFunc(world.FogLayers, func(l *FogLayer) bool { return l == layer })
+}
+
+// ClearFogLayers removes all fog layers from the world.
+func ClearFogLayers() {
+	world.FogLayers = nil
+}
+
+// SetDensityAlpha sets the density alpha parameters, including turning on and off.
+// When active, rendered points fade in proportion to how many other points lie within radius of them,
+// so dense, over-plotted regions of a point cloud don't saturate into solid blobs.
+// Points with count or fewer neighbors are drawn at full alpha. A radius of 0 or less has no effect.
+func SetDensityAlpha(active bool, radius, count float64) {
+	world.DensityActive = active
+	world.DensityRadius = radius
+	world.DensityCount = count
+}
+
+// SetTime sets the current time of an animation, in seconds.
+// This should be called once per frame when using time-based effects such as boil.
+func SetTime(t float64) {
+	world.Time = t
+}
+
+// SetBoil sets the boil parameters. Boil re-jitters projected points by up to amplitude pixels,
+// fps times per second of animation time (see SetTime), giving the "boiling" line effect of hand drawn animation.
+// The jitter only affects rendering. The 3d geometry is not changed. An amplitude of 0 turns boil off.
+// Each point of a shape keeps the same jitter throughout a time step, even while the shape moves.
+func SetBoil(amplitude, fps float64) {
+	world.BoilAmplitude = amplitude
+	world.BoilFPS = fps
+}
+
+// boilOffset returns the 2d boil jitter for the point at the given index of a shape with the given id,
+// for the current boil time step. The offset is the same for the same point throughout a time step,
+// however the shape moves.
+func boilOffset(id uint64, index int) (float64, float64) {
+	step := uint64(math.Floor(world.Time * world.BoilFPS))
+	h := mix64(step)
+	h = mix64(h ^ id)
+	h = mix64(h ^ uint64(index))
+	dx := float64(h>>11)/(1<<53)*2 - 1
+	h = mix64(h)
+	dy := float64(h>>11)/(1<<53)*2 - 1
+	return dx * world.BoilAmplitude, dy * world.BoilAmplitude
+}
+
+// mix64 is the splitmix64 finalizer, used to scramble bits for hashing.
+func mix64(h uint64) uint64 {
+	h += 0x9e3779b97f4a7c15
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
+
+// Parallel sets the number of goroutines used by heavy shape constructors such as GridBox and Isosurface,
+// and by rotations of large point lists.
+// A value less than 1 uses one goroutine per CPU. Default is 1, which does all the work on the calling goroutine.
+// When greater than 1, any functions passed to those constructors must be safe to call concurrently.
+func Parallel(n int) {
+	if n < 1 {
+		n = runtime.NumCPU()
+	}
+	world.Workers = n
+}
+
+// parallelFor calls f for every index from 0 to count-1, spread across the world's workers.
+// Each worker handles a contiguous block of indexes. parallelFor returns when all calls are complete.
+func parallelFor(count int, f func(i int)) {
+	parallelRange(count, func(start, end int) {
+		for i := start; i < end; i++ {
+			f(i)
+		}
+	})
+}
+
+// parallelRange splits the indexes from 0 to count-1 into one contiguous block per worker,
+// and calls f with the start and end (exclusive) of each block concurrently.
+// parallelRange returns when all calls are complete.
+func parallelRange(count int, f func(start, end int)) {
+	workers := min(world.Workers, count)
+	if workers <= 1 {
+		f(0, count)
+		return
+	}
+	var wg sync.WaitGroup
+	size := (count + workers - 1) / workers
+	for start := 0; start < count; start += size {
+		end := min(start+size, count)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f(start, end)
+		}()
+	}
+	wg.Wait()
+}
+
+// SetWidthSpace sets whether stroke widths and point radii are measured in world units (WorldSpace)
+// or in pixels (ScreenSpace). Default is WorldSpace.
+func SetWidthSpace(space WidthSpace) {
+	world.WidthSpace = space
+}
+
+// projectedSize returns the on screen size of a width or radius, for a point with the given scaling and z.
+func projectedSize(size, scaling, objectZ float64) float64 {
+	if world.DepthWidthFunc != nil {
+		size *= world.DepthWidthFunc(objectZ + world.CZ)
+	}
+	if world.WidthSpace == ScreenSpace {
+		return size * world.Supersample
+	}
+	return size * scaling
+}
+
+// SetSupersampling sets how many times larger the canvas is than the final output, for canvases drawn large
+// and scaled down to smooth their lines. ScreenSpace widths and radii are multiplied by it, so they are
+// the same number of pixels in the final output. WorldSpace sizes already scale with the focal length,
+// if it is set for the canvas size, as InitWorldForCanvas does. Default is 1.
+func SetSupersampling(factor float64) {
+	world.Supersample = factor
+}
+
+// SetPixelSnap sets whether line widths and coordinates snap to whole pixels, for crisp lines. Default is false.
+// Anti-aliasing is left on. For fully hard edges, turn it off on the Context yourself, such as with SetAntialias in cairo.
+func SetPixelSnap(active bool) {
+	world.PixelSnap = active
+}
+
+// snapWidth returns a line width rounded to whole pixels if pixel snapping is on.
+func snapWidth(width float64) float64 {
+	if !world.PixelSnap {
+		return width
+	}
+	return math.Max(1, math.Round(width))
+}
+
+// snapCoord returns a screen coordinate snapped for a line of the given width if pixel snapping is on:
+// to the center of a pixel for odd widths, or to the edge between pixels for even widths.
+// A width of 0 snaps to the edge.
+func snapCoord(v, width float64) float64 {
+	if !world.PixelSnap {
+		return v
+	}
+	if int(width)%2 == 1 {
+		return math.Floor(v) + 0.5
+	}
+	return math.Round(v)
+}
+
+// SetDepthWidthFunc sets a function of depth from the camera that multiplies line widths and point radii.
+// Pass nil to turn it off. Default is nil.
+func SetDepthWidthFunc(f func(z float64) float64) {
+	world.DepthWidthFunc = f
+}
+
+// SetDepthAlphaFunc sets a function that fades lines and points by depth, so opacity can fall off with distance
+// in any way, independent of fog. It is called with the depth of each line or point, as with SetDepthWidthFunc,
+// and returns a multiplier for its alpha, from 0 to 1. Pass nil to turn it off. Default is nil.
+func SetDepthAlphaFunc(f func(z float64) float64) {
+	world.DepthAlphaFunc = f
+}
+
+// SetAdaptiveSubdivision sets the longest line, in pixels, that a segment is drawn as.
+// Longer segments are split into equal pieces when stroked, each with its own fog and width,
+// so fog and width change smoothly along long segments near the camera rather than being averaged
+// over the whole segment. The shape itself is not changed. A length of 0 turns this off. Default is 0.
+func SetAdaptiveSubdivision(maxLength float64) {
+	world.MaxStrokeLength = maxLength
+}
+
+// SetDepthSubdivision sets the largest range of z that a segment is drawn across in a single piece.
+// Segments reaching farther into the distance are split into equal pieces when stroked, each with its own fog,
+// water level and width, so fog fades smoothly along long wires even when they point toward the camera and
+// are short on screen, which SetAdaptiveSubdivision doesn't catch. The shape itself is not changed.
+// A depth of 0 turns this off. Default is 0.
+func SetDepthSubdivision(maxDepth float64) {
+	world.MaxStrokeDepth = maxDepth
+}
+
+// SetDepthSort sets whether the segments of each shape are drawn from back to front, by the average z of their points,
+// rather than in the order they were added. Nearer translucent or fogged lines are then drawn over farther ones,
+// so they layer correctly. Sorting is within each shape, not between shapes. Default is false.
+func SetDepthSort(active bool) {
+	world.DepthSort = active
+}
+
+// SetBackfaceCulling sets whether shapes with faces skip the segments that only belong to faces turned away
+// from the viewer, so the far side of a closed shape such as a sphere or box is hidden. Segments on the edge
+// between front and back faces are kept, so silhouettes stay intact, as are segments not on any face.
+// Faces turned away are also skipped by Fill. Default is false.
+func SetBackfaceCulling(active bool) {
+	world.BackfaceCulling = active
+}
+
 // SetFont sets the font type, size and spacing for future text objects.
 // Size is the width of a single letter. Default 100.
 // Spacing is the space between letters, as a percentage of letter width. Defaults to 0.2.